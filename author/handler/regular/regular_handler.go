@@ -0,0 +1,69 @@
+package regular
+
+import (
+	"encoding/json"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/author/model"
+	"github.com/printezisn/serverless-blog-back/author/service/generic"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	"github.com/printezisn/serverless-blog-back/global/middleware"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/router"
+	"github.com/printezisn/serverless-blog-back/global/writer"
+)
+
+// Routes returns a function that registers the routes of author profiles to a router. Everyone can read a profile,
+// but only authors, editors and admins can change them.
+func Routes(service generic.Service) func(r *router.Router) {
+	with := func(handle func(generic.Service, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse,
+		error)) router.Handler {
+		return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			return handle(service, request)
+		}
+	}
+	authors := middleware.RequireRole(auth.RoleAuthor)
+
+	return func(r *router.Router) {
+		r.Handle("PUT", "/authors", authors(with(createAuthor)))
+		r.Handle("POST", "/authors", authors(with(updateAuthor)))
+		r.Handle("GET", "/authors/{id}", with(getAuthor))
+		r.Handle("DELETE", "/authors/{id}", authors(with(deleteAuthor)))
+	}
+}
+
+func createAuthor(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	var author model.Author
+	err := json.Unmarshal([]byte(request.Body), &author)
+	if err != nil {
+		return writer.Error(400, globalModel.NewError(globalModel.CodeInvalidInput, "The input model is not valid.")), nil
+	}
+
+	return writer.Response(service.Create(caller(request), author)), nil
+}
+
+func updateAuthor(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	var author model.Author
+	err := json.Unmarshal([]byte(request.Body), &author)
+	if err != nil {
+		return writer.Error(400, globalModel.NewError(globalModel.CodeInvalidInput, "The input model is not valid.")), nil
+	}
+
+	return writer.Response(service.Update(caller(request), author)), nil
+}
+
+func getAuthor(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return writer.Response(service.Get(request.PathParameters["id"])), nil
+}
+
+func deleteAuthor(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return writer.Response(service.Delete(caller(request), request.PathParameters["id"])), nil
+}
+
+// caller returns the identity of the caller of a request. The routes that need it are only available to authenticated
+// callers.
+func caller(request events.APIGatewayProxyRequest) auth.Identity {
+	identity, _ := auth.FromRequest(request)
+
+	return identity
+}
@@ -0,0 +1,112 @@
+package regular
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/printezisn/serverless-blog-back/author/model"
+	"github.com/printezisn/serverless-blog-back/author/service/mocks"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/router"
+)
+
+// author is the identity of the caller of authorContext.
+var author = auth.Identity{Subject: "user", Groups: []string{"author"}}
+
+// newRouter returns a router with the routes of author profiles.
+func newRouter(service *mocks.Service) *router.Router {
+	r := router.New()
+	Routes(service)(r)
+
+	return r
+}
+
+// TestHandleCreate tests that the PUT "/authors" request creates the profile of the caller.
+func TestHandleCreate(t *testing.T) {
+	service := new(mocks.Service)
+	profile := model.Author{DisplayName: "name"}
+	profileBytes, _ := json.Marshal(profile)
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+
+	service.On("Create", author, profile).Return(expectedResponse)
+
+	response, _ := newRouter(service).Route(events.APIGatewayProxyRequest{Path: "/authors", HTTPMethod: "PUT",
+		Body: string(profileBytes), RequestContext: authorContext()})
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestHandleUpdate tests that the POST "/authors" request updates a profile.
+func TestHandleUpdate(t *testing.T) {
+	service := new(mocks.Service)
+	profile := model.Author{ID: "user", DisplayName: "name"}
+	profileBytes, _ := json.Marshal(profile)
+
+	service.On("Update", author, profile).Return(globalModel.Response{Entity: profile, StatusCode: 200})
+
+	response, _ := newRouter(service).Route(events.APIGatewayProxyRequest{Path: "/authors", HTTPMethod: "POST",
+		Body: string(profileBytes), RequestContext: authorContext()})
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestHandleGetAndDelete tests that the GET and DELETE "/authors/{id}" requests pass the id to the service.
+func TestHandleGetAndDelete(t *testing.T) {
+	service := new(mocks.Service)
+
+	service.On("Get", "id").Return(globalModel.Response{Entity: model.Author{ID: "id"}, StatusCode: 200})
+	service.On("Delete", author, "id").Return(globalModel.Response{Entity: "id", StatusCode: 200})
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/authors/id", HTTPMethod: "GET"},
+		{Path: "/authors/id", HTTPMethod: "DELETE", RequestContext: authorContext()},
+	}
+
+	for _, request := range requests {
+		response, _ := newRouter(service).Route(request)
+
+		if response.StatusCode != 200 {
+			t.Errorf("The status code of %s was expected to be 200, but it was %d.", request.HTTPMethod,
+				response.StatusCode)
+		}
+	}
+}
+
+// TestHandleWithInvalidInput tests that the requests are rejected when the caller is not authenticated or the body
+// is not valid.
+func TestHandleWithInvalidInput(t *testing.T) {
+	service := new(mocks.Service)
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/authors", HTTPMethod: "PUT", Body: "{}"},
+		{Path: "/authors/id", HTTPMethod: "DELETE"},
+		{Path: "/authors", HTTPMethod: "PUT", Body: "error", RequestContext: authorContext()},
+		{Path: "/authors", HTTPMethod: "POST", Body: "error", RequestContext: authorContext()},
+	}
+	expectedStatusCodes := []int{401, 401, 400, 400}
+
+	for i, request := range requests {
+		response, _ := newRouter(service).Route(request)
+
+		if response.StatusCode != expectedStatusCodes[i] {
+			t.Errorf("The status code of %s %s was expected to be %d, but it was %d.", request.HTTPMethod, request.Path,
+				expectedStatusCodes[i], response.StatusCode)
+		}
+	}
+	service.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// authorContext returns the request context of an author that the Cognito authorizer has authenticated.
+func authorContext() events.APIGatewayProxyRequestContext {
+	return events.APIGatewayProxyRequestContext{
+		Authorizer: map[string]interface{}{"claims": map[string]interface{}{"sub": "user", "cognito:groups": "author"}},
+	}
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/rules"
+)
+
+// Author represents the profile of an author. Its id is the Cognito subject of the author, which is also the author
+// id of the blog posts that the author has written.
+type Author struct {
+	ID                string `json:"id"`
+	DisplayName       string `json:"displayName"`
+	Bio               string `json:"bio"`
+	AvatarURL         string `json:"avatarUrl"`
+	CreationTimestamp int64  `json:"creationTimestamp"`
+	UpdateTimestamp   int64  `json:"updateTimestamp"`
+}
+
+// Validate checks if an Author instance is valid and returns its errors, or an empty list if it's valid.
+func (author Author) Validate() []globalModel.Error {
+	err := validation.ValidateStruct(
+		&author,
+		validation.Field(
+			&author.ID,
+			rules.Required("The id is required."),
+			rules.MaxLength(250, "The id may have up to 250 characters.")),
+		validation.Field(
+			&author.DisplayName,
+			rules.Required("The display name is required."),
+			rules.MaxLength(100, "The display name may have up to 100 characters.")),
+		validation.Field(
+			&author.Bio,
+			rules.MaxLength(2000, "The bio may have up to 2000 characters.")),
+		validation.Field(
+			&author.AvatarURL,
+			rules.MaxLength(500, "The avatar URL may have up to 500 characters."),
+			rules.URL("The avatar URL is not valid.")))
+
+	return rules.Errors(err)
+}
@@ -0,0 +1,32 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidate tests that Validate returns errors for missing, long and malformed fields.
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		author    Author
+		hasErrors bool
+	}{
+		{Author{}, true},
+		{Author{ID: "test_id"}, true},
+		{Author{ID: "test_id", DisplayName: "test_name"}, false},
+		{Author{ID: "test_id", DisplayName: strings.Repeat("a", 101)}, true},
+		{Author{ID: "test_id", DisplayName: "test_name", Bio: strings.Repeat("a", 2001)}, true},
+		{Author{ID: "test_id", DisplayName: "test_name", AvatarURL: "avatar"}, true},
+		{Author{ID: "test_id", DisplayName: "test_name", Bio: "test_bio",
+			AvatarURL: "https://example.com/avatar.png"}, false},
+	}
+
+	for _, testCase := range testCases {
+		errs := testCase.author.Validate()
+		if testCase.hasErrors && len(errs) == 0 {
+			t.Error("The following test case was supposed to have errors, but it didn't: ", testCase)
+		} else if !testCase.hasErrors && len(errs) > 0 {
+			t.Error("The following test case wasn't supposed to have errors, but it did: ", testCase)
+		}
+	}
+}
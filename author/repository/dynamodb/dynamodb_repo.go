@@ -0,0 +1,146 @@
+package dynamodb
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/printezisn/serverless-blog-back/author/model"
+	"github.com/printezisn/serverless-blog-back/author/repository/generic"
+)
+
+// Repo represents a repository for author profiles that uses DynamoDB.
+type Repo struct {
+	tableName string
+	client    *dynamodb.DynamoDB
+}
+
+// New returns a new repository instance for author profiles that uses DynamoDB.
+func New() Repo {
+	tableName, ok := os.LookupEnv("DYNAMODB_AUTHORS_TABLE_NAME")
+	if !ok {
+		tableName = "authors"
+	}
+
+	return Repo{tableName: tableName, client: nil}
+}
+
+// createClient creates a new DynamoDB client. If the DYNAMODB_ENDPOINT environment variable is set, the client
+// connects to that endpoint instead (e.g. DynamoDB Local).
+func (repo *Repo) createClient() {
+	if repo.client == nil {
+		session := session.Must(session.NewSessionWithOptions(session.Options{
+			SharedConfigState: session.SharedConfigEnable,
+		}))
+
+		config := aws.NewConfig()
+		if endpoint, ok := os.LookupEnv("DYNAMODB_ENDPOINT"); ok {
+			config = config.WithEndpoint(endpoint)
+		}
+
+		repo.client = dynamodb.New(session, config)
+	}
+}
+
+// Create creates a new author profile in the database.
+func (repo *Repo) Create(author model.Author) (model.Author, error) {
+	return repo.put(author, "attribute_not_exists(id)", generic.ErrAlreadyExists)
+}
+
+// Update replaces an existing author profile in the database.
+func (repo *Repo) Update(author model.Author) (model.Author, error) {
+	return repo.put(author, "attribute_exists(id)", generic.ErrNotFound)
+}
+
+// put writes an author profile to the database if a condition holds. A failed condition is translated to
+// conditionalErr.
+func (repo *Repo) put(author model.Author, condition string, conditionalErr error) (model.Author, error) {
+	repo.createClient()
+
+	item, err := dynamodbattribute.MarshalMap(author)
+	if err != nil {
+		return author, err
+	}
+
+	_, err = repo.client.PutItem(&dynamodb.PutItemInput{
+		Item:                item,
+		TableName:           aws.String(repo.tableName),
+		ConditionExpression: aws.String(condition),
+	})
+
+	return author, translateError(err, conditionalErr)
+}
+
+// Get searches and returns an author profile based on its id.
+func (repo *Repo) Get(id string) (model.Author, bool, error) {
+	repo.createClient()
+
+	response, err := repo.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(repo.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(id),
+			},
+		},
+	})
+	if err != nil {
+		return model.Author{}, false, translateError(err, nil)
+	}
+	if len(response.Item) == 0 {
+		return model.Author{}, false, nil
+	}
+
+	var author model.Author
+	if err = dynamodbattribute.UnmarshalMap(response.Item, &author); err != nil {
+		return model.Author{}, false, err
+	}
+
+	return author, true, nil
+}
+
+// Delete deletes an author profile from the database. It returns false if there was no profile with the id.
+func (repo *Repo) Delete(id string) (bool, error) {
+	repo.createClient()
+
+	response, err := repo.client.DeleteItem(&dynamodb.DeleteItemInput{
+		TableName: aws.String(repo.tableName),
+		Key: map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(id),
+			},
+		},
+		ReturnValues: aws.String(dynamodb.ReturnValueAllOld),
+	})
+	if err != nil {
+		return false, translateError(err, nil)
+	}
+
+	return len(response.Attributes) > 0, nil
+}
+
+// translateError translates a DynamoDB error to the matching repository error, keeping the original error in the
+// message. A failed condition expression is translated to conditionalErr.
+func translateError(err error, conditionalErr error) error {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return err
+	}
+
+	var repoErr error
+	switch awsErr.Code() {
+	case dynamodb.ErrCodeConditionalCheckFailedException:
+		repoErr = conditionalErr
+	case dynamodb.ErrCodeProvisionedThroughputExceededException, dynamodb.ErrCodeRequestLimitExceeded,
+		"ThrottlingException":
+		repoErr = generic.ErrThrottled
+	}
+	if repoErr == nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %v", repoErr, err)
+}
@@ -0,0 +1,14 @@
+package generic
+
+import "errors"
+
+// The errors that a repository returns (possibly wrapped) when an operation fails for a known reason. They allow the
+// service layer to react to failures without knowing which database is used.
+var (
+	// ErrAlreadyExists is returned when an author profile is created with an id that is already used.
+	ErrAlreadyExists = errors.New("the author already exists")
+	// ErrNotFound is returned when an operation requires an author profile that doesn't exist.
+	ErrNotFound = errors.New("the author was not found")
+	// ErrThrottled is returned when the database rejects the operation because of too many requests.
+	ErrThrottled = errors.New("the operation was throttled")
+)
@@ -0,0 +1,13 @@
+package generic
+
+import "github.com/printezisn/serverless-blog-back/author/model"
+
+// Repo represents the repository layer for author profiles. Create fails with ErrAlreadyExists if there is already a
+// profile with the same id, and Update fails with ErrNotFound if there isn't one. Delete reports whether a profile was
+// deleted.
+type Repo interface {
+	Create(author model.Author) (model.Author, error)
+	Update(author model.Author) (model.Author, error)
+	Get(id string) (model.Author, bool, error)
+	Delete(id string) (bool, error)
+}
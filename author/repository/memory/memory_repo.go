@@ -0,0 +1,68 @@
+package memory
+
+import (
+	"sync"
+
+	"github.com/printezisn/serverless-blog-back/author/model"
+	"github.com/printezisn/serverless-blog-back/author/repository/generic"
+)
+
+// Repo represents a repository for author profiles that keeps everything in memory. It is safe for concurrent use.
+type Repo struct {
+	mutex   *sync.RWMutex
+	authors map[string]model.Author
+}
+
+// New returns a new repository instance for author profiles that keeps everything in memory.
+func New() Repo {
+	return Repo{mutex: &sync.RWMutex{}, authors: map[string]model.Author{}}
+}
+
+// Create creates a new author profile in memory.
+func (repo *Repo) Create(author model.Author) (model.Author, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	if _, ok := repo.authors[author.ID]; ok {
+		return author, generic.ErrAlreadyExists
+	}
+	repo.authors[author.ID] = author
+
+	return author, nil
+}
+
+// Update replaces an existing author profile in memory.
+func (repo *Repo) Update(author model.Author) (model.Author, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	if _, ok := repo.authors[author.ID]; !ok {
+		return author, generic.ErrNotFound
+	}
+	repo.authors[author.ID] = author
+
+	return author, nil
+}
+
+// Get searches and returns an author profile based on its id.
+func (repo *Repo) Get(id string) (model.Author, bool, error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	author, ok := repo.authors[id]
+
+	return author, ok, nil
+}
+
+// Delete deletes an author profile from memory. It returns false if there was no profile with the id.
+func (repo *Repo) Delete(id string) (bool, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	if _, ok := repo.authors[id]; !ok {
+		return false, nil
+	}
+	delete(repo.authors, id)
+
+	return true, nil
+}
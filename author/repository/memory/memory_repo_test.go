@@ -0,0 +1,40 @@
+package memory
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/printezisn/serverless-blog-back/author/model"
+	"github.com/printezisn/serverless-blog-back/author/repository/generic"
+)
+
+// TestRepo tests that author profiles can be created, updated, fetched and deleted.
+func TestRepo(t *testing.T) {
+	repo := New()
+	author := model.Author{ID: "id", DisplayName: "name"}
+
+	if _, err := repo.Create(author); err != nil {
+		t.Fatal("The creation was expected to succeed, but it failed with ", err)
+	}
+	if _, err := repo.Create(author); !errors.Is(err, generic.ErrAlreadyExists) {
+		t.Error("The error was expected to be ErrAlreadyExists, but it was ", err)
+	}
+
+	author.Bio = "bio"
+	if _, err := repo.Update(author); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+	if actualAuthor, found, _ := repo.Get("id"); !found || actualAuthor != author {
+		t.Errorf("The author was expected to be %+v, but it was %+v.", author, actualAuthor)
+	}
+
+	if deleted, err := repo.Delete("id"); !deleted || err != nil {
+		t.Error("The author was expected to be deleted, but it wasn't: ", err)
+	}
+	if deleted, _ := repo.Delete("id"); deleted {
+		t.Error("The author was expected to be deleted already.")
+	}
+	if _, err := repo.Update(author); !errors.Is(err, generic.ErrNotFound) {
+		t.Error("The error was expected to be ErrNotFound, but it was ", err)
+	}
+}
@@ -0,0 +1,16 @@
+package generic
+
+import (
+	"github.com/printezisn/serverless-blog-back/author/model"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// Service represents the service layer for author profiles. The operations that change profiles take the identity of
+// the caller, so that they can check its permissions.
+type Service interface {
+	Create(caller auth.Identity, author model.Author) globalModel.Response
+	Update(caller auth.Identity, author model.Author) globalModel.Response
+	Get(id string) globalModel.Response
+	Delete(caller auth.Identity, id string) globalModel.Response
+}
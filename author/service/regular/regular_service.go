@@ -0,0 +1,150 @@
+package regular
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"github.com/printezisn/serverless-blog-back/author/model"
+	authorRepo "github.com/printezisn/serverless-blog-back/author/repository/generic"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// Service represents the regular service layer for author profiles.
+type Service struct {
+	repo authorRepo.Repo
+}
+
+// New creates a new instance of the regular service layer for author profiles.
+func New(repo authorRepo.Repo) Service {
+	return Service{repo: repo}
+}
+
+// Create creates the profile of an author. A profile without an id belongs to the caller, and only admins may create
+// the profiles of others.
+func (service *Service) Create(caller auth.Identity, author model.Author) globalModel.Response {
+	if author.ID == "" {
+		author.ID = caller.Subject
+	}
+	if !canChange(caller, author.ID) {
+		return forbidden(author, "Only admins may create the profiles of others.")
+	}
+	errs := author.Validate()
+	if len(errs) > 0 {
+		return globalModel.Response{Entity: author, Errors: errs, StatusCode: 400}
+	}
+
+	author.CreationTimestamp = time.Now().UTC().Unix()
+	author.UpdateTimestamp = author.CreationTimestamp
+
+	newAuthor, err := service.repo.Create(author)
+	if err != nil {
+		log.Println("An error occurred while creating an author profile: ", err)
+
+		if errors.Is(err, authorRepo.ErrAlreadyExists) {
+			return globalModel.Response{Entity: newAuthor, Errors: []globalModel.Error{
+				globalModel.NewError(globalModel.CodeConflict, "The profile already exists.")}, StatusCode: 409}
+		}
+
+		return globalModel.Response{Entity: newAuthor, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+
+	return globalModel.Response{Entity: newAuthor, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// Update updates the profile of an author. Authors may only update their own profile, and admins every profile.
+func (service *Service) Update(caller auth.Identity, author model.Author) globalModel.Response {
+	if author.ID == "" {
+		author.ID = caller.Subject
+	}
+	if !canChange(caller, author.ID) {
+		return forbidden(author, "The caller may only change their own profile.")
+	}
+	errs := author.Validate()
+	if len(errs) > 0 {
+		return globalModel.Response{Entity: author, Errors: errs, StatusCode: 400}
+	}
+
+	currentAuthor, found, err := service.repo.Get(author.ID)
+	if err != nil {
+		log.Println("An error occurred while fetching an author profile: ", err)
+		return globalModel.Response{Entity: author, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found {
+		return globalModel.Response{Entity: author, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+
+	author.CreationTimestamp = currentAuthor.CreationTimestamp
+	author.UpdateTimestamp = time.Now().UTC().Unix()
+
+	updatedAuthor, err := service.repo.Update(author)
+	if err != nil {
+		log.Println("An error occurred while updating an author profile: ", err)
+
+		if errors.Is(err, authorRepo.ErrNotFound) {
+			return globalModel.Response{Entity: updatedAuthor, Errors: []globalModel.Error{}, StatusCode: 404}
+		}
+
+		return globalModel.Response{Entity: updatedAuthor, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+
+	return globalModel.Response{Entity: updatedAuthor, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// Get fetches the profile of an author.
+func (service *Service) Get(id string) globalModel.Response {
+	author, found, err := service.repo.Get(id)
+	if err != nil {
+		log.Println("An error occurred while fetching an author profile: ", err)
+		return globalModel.Response{Entity: author, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found {
+		return globalModel.Response{Entity: model.Author{}, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+
+	return globalModel.Response{Entity: author, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// Delete deletes the profile of an author. Authors may only delete their own profile, and admins every profile. The
+// blog posts of the author are kept.
+func (service *Service) Delete(caller auth.Identity, id string) globalModel.Response {
+	if !canChange(caller, id) {
+		return forbidden(id, "The caller may only change their own profile.")
+	}
+
+	deleted, err := service.repo.Delete(id)
+	if err != nil {
+		log.Println("An error occurred while deleting an author profile: ", err)
+		return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !deleted {
+		return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+
+	return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// canChange checks if a caller may change the profile with an id. Admins may change every profile and authors only
+// their own one.
+func canChange(caller auth.Identity, id string) bool {
+	if caller.HasRole(auth.RoleAdmin) {
+		return true
+	}
+
+	return caller.HasRole(auth.RoleAuthor) && id != "" && id == caller.Subject
+}
+
+// forbidden returns the response of an operation that the caller is not allowed to do.
+func forbidden(entity interface{}, message string) globalModel.Response {
+	return globalModel.Response{Entity: entity, Errors: []globalModel.Error{globalModel.NewError(globalModel.CodeForbidden, message)}, StatusCode: 403}
+}
+
+// errorStatusCode returns the status code for an unexpected repository error.
+func errorStatusCode(err error) int {
+	if errors.Is(err, authorRepo.ErrThrottled) {
+		return 503
+	}
+
+	return 500
+}
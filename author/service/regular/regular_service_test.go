@@ -0,0 +1,158 @@
+package regular
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+
+	"github.com/printezisn/serverless-blog-back/author/model"
+	"github.com/printezisn/serverless-blog-back/author/repository/generic"
+	repoMocks "github.com/printezisn/serverless-blog-back/author/repository/mocks"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+)
+
+var (
+	// author is a caller that may only change their own profile.
+	author = auth.Identity{Subject: "author", Groups: []string{"author"}}
+	// admin is a caller that may change every profile.
+	admin = auth.Identity{Subject: "admin", Groups: []string{"admin"}}
+)
+
+// TestCreateWithSuccess tests that the Create method creates the profile of the caller when there is no id.
+func TestCreateWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	profile := model.Author{DisplayName: "name"}
+
+	repo.On("Create", mock.MatchedBy(func(actualAuthor model.Author) bool {
+		return actualAuthor.ID == "author" && actualAuthor.CreationTimestamp > 0
+	})).Return(model.Author{ID: "author", DisplayName: "name"}, nil)
+
+	response := service.Create(author, profile)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestCreateWithErrors tests that the Create method returns the correct status code for forbidden, invalid and
+// existing profiles.
+func TestCreateWithErrors(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("Create", mock.Anything).Return(model.Author{}, generic.ErrAlreadyExists)
+
+	testCases := []struct {
+		caller     auth.Identity
+		profile    model.Author
+		statusCode int
+	}{
+		{author, model.Author{ID: "other", DisplayName: "name"}, 403},
+		{auth.Identity{Subject: "reader"}, model.Author{DisplayName: "name"}, 403},
+		{author, model.Author{}, 400},
+		{admin, model.Author{ID: "other", DisplayName: "name"}, 409},
+	}
+
+	for _, testCase := range testCases {
+		response := service.Create(testCase.caller, testCase.profile)
+
+		if response.StatusCode != testCase.statusCode {
+			t.Errorf("The status code for %+v was expected to be %d, but it was %d.", testCase.profile,
+				testCase.statusCode, response.StatusCode)
+		}
+	}
+}
+
+// TestUpdateWithSuccess tests that the Update method keeps the creation timestamp of the profile.
+func TestUpdateWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	profile := model.Author{ID: "author", DisplayName: "new name"}
+
+	repo.On("Get", "author").Return(model.Author{ID: "author", DisplayName: "name", CreationTimestamp: 10}, true, nil)
+	repo.On("Update", mock.MatchedBy(func(actualAuthor model.Author) bool {
+		return actualAuthor.DisplayName == "new name" && actualAuthor.CreationTimestamp == 10
+	})).Return(profile, nil)
+
+	response := service.Update(author, profile)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestUpdateWithErrors tests that the Update method rejects the profiles of others and missing profiles.
+func TestUpdateWithErrors(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("Get", "missing").Return(model.Author{}, false, nil)
+	repo.On("Get", "other").Return(model.Author{}, false, errors.New("unexpected error"))
+
+	testCases := []struct {
+		caller     auth.Identity
+		profile    model.Author
+		statusCode int
+	}{
+		{author, model.Author{ID: "other", DisplayName: "name"}, 403},
+		{admin, model.Author{ID: "missing", DisplayName: "name"}, 404},
+		{admin, model.Author{ID: "other", DisplayName: "name"}, 500},
+	}
+
+	for _, testCase := range testCases {
+		response := service.Update(testCase.caller, testCase.profile)
+
+		if response.StatusCode != testCase.statusCode {
+			t.Errorf("The status code for %+v was expected to be %d, but it was %d.", testCase.profile,
+				testCase.statusCode, response.StatusCode)
+		}
+	}
+	repo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// TestGet tests that the Get method returns the profile, or a 404 if there is none.
+func TestGet(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	profile := model.Author{ID: "author", DisplayName: "name"}
+
+	repo.On("Get", "author").Return(profile, true, nil)
+	repo.On("Get", "missing").Return(model.Author{}, false, nil)
+
+	if response := service.Get("author"); response.StatusCode != 200 || response.Entity != profile {
+		t.Errorf("The response was expected to contain %+v, but it was %+v.", profile, response)
+	}
+	if response := service.Get("missing"); response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestDelete tests that the Delete method deletes the profile of the caller, but not the profiles of others.
+func TestDelete(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("Delete", "author").Return(true, nil)
+	repo.On("Delete", "missing").Return(false, nil)
+
+	testCases := []struct {
+		caller     auth.Identity
+		id         string
+		statusCode int
+	}{
+		{author, "author", 200},
+		{author, "other", 403},
+		{admin, "missing", 404},
+	}
+
+	for _, testCase := range testCases {
+		response := service.Delete(testCase.caller, testCase.id)
+
+		if response.StatusCode != testCase.statusCode {
+			t.Errorf("The status code for %s was expected to be %d, but it was %d.", testCase.id, testCase.statusCode,
+				response.StatusCode)
+		}
+	}
+}
@@ -6,3 +6,8 @@ import "github.com/aws/aws-lambda-go/events"
 type Handler interface {
 	Handle(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
 }
+
+// ScheduledHandler handles scheduled events for blog posts
+type ScheduledHandler interface {
+	Handle(event events.CloudWatchEvent) error
+}
@@ -1,213 +1,574 @@
 package regular
 
 import (
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/printezisn/serverless-blog-back/blogpost/model"
 	"github.com/printezisn/serverless-blog-back/blogpost/service/generic"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	"github.com/printezisn/serverless-blog-back/global/middleware"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/router"
+	"github.com/printezisn/serverless-blog-back/global/writer"
 )
 
 // Handler handles requests for blog posts.
 type Handler struct {
 	service generic.Service
+	router  *router.Router
 }
 
-// New creates and returns a new handler instance.
-func New(service generic.Service) Handler {
-	return Handler{service: service}
+// New creates and returns a new handler instance. The routes of other resources, such as the profiles of authors, can
+// be registered as well, so that a single function serves every request through the same middlewares.
+func New(service generic.Service, routes ...func(r *router.Router)) Handler {
+	r := newRouter(service)
+	for _, register := range routes {
+		register(r)
+	}
+
+	return Handler{service: service, router: r}
 }
 
 // Handle handles requests from the API Gateway.
 func (handle *Handler) Handle(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	path := strings.ToLower(request.Path)
-	if strings.Index(path, "/posts") == 0 {
-		if strings.ToLower(request.HTTPMethod) == "put" {
-			return createBlogPost(handle.service, request)
-		}
-		if strings.ToLower(request.HTTPMethod) == "post" {
-			return updateBlogPost(handle.service, request)
-		}
-		if strings.ToLower(request.HTTPMethod) == "delete" {
-			return deleteBlogPost(handle.service, request)
+	return handle.router.Route(request)
+}
+
+// newRouter returns the router with the routes of every resource. The routes that change blog posts and the routes of
+// drafts and of the trash are only available to authors, editors and admins. Every request goes through the shared
+// middlewares.
+func newRouter(service generic.Service) *router.Router {
+	with := func(handle func(generic.Service, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse,
+		error)) router.Handler {
+		return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			return handle(service, request)
 		}
-		if strings.ToLower(request.HTTPMethod) == "get" {
-			if request.PathParameters["id"] != "" {
-				return getBlogPost(handle.service, request)
-			}
-			if request.QueryStringParameters["lastID"] != "" {
-				return getMoreBlogPosts(handle.service, request)
-			}
-
-			return getAllBlogPosts(handle.service, request)
+	}
+
+	authors := middleware.RequireRole(auth.RoleAuthor)
+
+	r := router.New()
+	r.Use(middleware.CORS(middleware.NewCORSPolicy()), middleware.RequestID, middleware.Logging, middleware.Recover,
+		middleware.RequireJSON)
+	r.NotFound = notFound
+	r.MethodNotAllowed = methodNotAllowed
+	r.Options = options
+
+	r.Handle("GET", "/posts", with(listBlogPosts))
+	r.Handle("PUT", "/posts", authors(with(createBlogPost)))
+	r.Handle("POST", "/posts", authors(with(updateBlogPost)))
+	r.Handle("GET", "/posts/{id}", with(getBlogPost))
+	r.Handle("PATCH", "/posts/{id}", authors(with(patchBlogPost)))
+	r.Handle("DELETE", "/posts/{id}", authors(with(deleteBlogPost)))
+	r.Handle("POST", "/posts/{id}/restore", authors(with(restoreBlogPost)))
+	r.Handle("GET", "/posts/{id}/revisions", with(getRevisions))
+	r.Handle("GET", "/posts/{id}/revisions/{revision}", with(getRevision))
+	r.Handle("POST", "/posts/{id}/revisions/{revision}/restore", authors(with(restoreRevision)))
+	r.Handle("GET", "/posts/{id}/diff", with(getDiff))
+
+	r.Handle("GET", "/drafts", authors(with(listDrafts)))
+	r.Handle("GET", "/drafts/{id}", authors(with(getDraft)))
+	r.Handle("GET", "/drafts/{id}/revisions", authors(with(getDraftRevisions)))
+	r.Handle("GET", "/drafts/{id}/revisions/{revision}", authors(with(getDraftRevision)))
+	r.Handle("GET", "/drafts/{id}/diff", authors(with(getDraftDiff)))
+
+	r.Handle("GET", "/trash", authors(with(listTrash)))
+
+	r.Handle("GET", "/authors/{id}/posts", with(listAuthorBlogPosts))
+
+	r.Handle("GET", "/tags", with(getTags))
+
+	return r
+}
+
+func listBlogPosts(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.QueryStringParameters["category"] != "" {
+		if request.QueryStringParameters["cursor"] != "" {
+			return getMoreBlogPostsByCategory(service, request)
 		}
-		if strings.ToLower(request.HTTPMethod) == "options" {
-			return events.APIGatewayProxyResponse{
-					Body: "Success",
-					Headers: map[string]string{
-						"Content-Type":                 "application/text",
-						"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-						"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-						"Access-Control-Allow-Origin":  "*",
-					},
-					StatusCode: 200},
-				nil
+
+		return getAllBlogPostsByCategory(service, request)
+	}
+	if request.QueryStringParameters["tag"] != "" {
+		if request.QueryStringParameters["cursor"] != "" {
+			return getMoreBlogPostsByTag(service, request)
 		}
+
+		return getAllBlogPostsByTag(service, request)
+	}
+	if request.QueryStringParameters["cursor"] != "" {
+		return getMoreBlogPosts(service, request)
+	}
+
+	return getAllBlogPosts(service, request)
+}
+
+func listDrafts(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.QueryStringParameters["cursor"] != "" {
+		return getMoreDrafts(service, request)
 	}
 
-	return events.APIGatewayProxyResponse{
-			Body: "The request is not supported",
-			Headers: map[string]string{
-				"Content-Type":                 "application/text",
-				"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-				"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-				"Access-Control-Allow-Origin":  "*",
-			},
-			StatusCode: 400},
-		nil
+	return getAllDrafts(service, request)
+}
+
+func listTrash(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	if request.QueryStringParameters["cursor"] != "" {
+		return getMoreTrash(service, request)
+	}
+
+	return getAllTrash(service, request)
+}
+
+// options answers the preflight requests of every route.
+func options(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return writer.Text(200, "Success"), nil
+}
+
+// notFound responds to the requests whose path matches no route.
+func notFound(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return writer.Error(404), nil
+}
+
+// methodNotAllowed responds to the requests whose method is not supported by their route.
+func methodNotAllowed(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return writer.Error(405), nil
 }
 
 func createBlogPost(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	var post model.BlogPost
 	err := json.Unmarshal([]byte(request.Body), &post)
 	if err != nil {
-		return events.APIGatewayProxyResponse{
-				Body: "The input model is not valid.",
-				Headers: map[string]string{
-					"Content-Type":                 "application/text",
-					"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-					"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-					"Access-Control-Allow-Origin":  "*",
-				},
-				StatusCode: 400,
-			},
-			nil
-	}
-
-	response := service.Create(post)
-	responseBytes, _ := json.Marshal(response)
-
-	return events.APIGatewayProxyResponse{
-			Body: string(responseBytes),
-			Headers: map[string]string{
-				"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-				"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-				"Access-Control-Allow-Origin":  "*",
-			},
-			StatusCode: response.StatusCode,
-		},
-		nil
+		return writer.Error(400, globalModel.NewError(globalModel.CodeInvalidInput, "The input model is not valid.")), nil
+	}
+
+	return writer.Response(service.Create(caller(request), post)), nil
 }
 
 func updateBlogPost(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	var post model.BlogPost
 	err := json.Unmarshal([]byte(request.Body), &post)
 	if err != nil {
-		return events.APIGatewayProxyResponse{
-				Body: "The input model is not valid.",
-				Headers: map[string]string{
-					"Content-Type":                 "application/text",
-					"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-					"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-					"Access-Control-Allow-Origin":  "*",
-				},
-				StatusCode: 400,
-			},
-			nil
-	}
-
-	response := service.Update(post)
-	responseBytes, _ := json.Marshal(response)
-
-	return events.APIGatewayProxyResponse{
-			Body: string(responseBytes),
-			Headers: map[string]string{
-				"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-				"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-				"Access-Control-Allow-Origin":  "*",
-			},
-			StatusCode: response.StatusCode,
-		},
-		nil
+		return writer.Error(400, globalModel.NewError(globalModel.CodeInvalidInput, "The input model is not valid.")), nil
+	}
+
+	// The If-Match header is an alternative to the revision in the body. If both are given, they must agree.
+	revision, conditional, ok := ifMatchRevision(request, post.ID)
+	if conditional && (!ok || (revision != 0 && post.Revision != 0 && post.Revision != revision)) {
+		return writer.Error(412, globalModel.NewError(globalModel.CodePreconditionFailed,
+			"The blog post doesn't match the If-Match header.")), nil
+	}
+	if post.Revision == 0 {
+		post.Revision = revision
+	}
+	// A "*" matches every revision, so the blog post is updated at its current one.
+	if conditional && post.Revision == 0 {
+		current := service.GetDraft(caller(request), post.ID)
+		currentPost, ok := current.Entity.(model.BlogPost)
+		if current.StatusCode != 200 || !ok {
+			return writer.Response(current), nil
+		}
+		post.Revision = currentPost.Revision
+	}
+
+	response := service.Update(caller(request), post)
+	if conditional && response.StatusCode == 409 {
+		response.StatusCode = 412
+	}
+
+	return withEntityTag(writer.Response(response), response), nil
+}
+
+func patchBlogPost(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	revision, conditional, ok := ifMatchRevision(request, id)
+	if conditional && !ok {
+		return writer.Error(412, globalModel.NewError(globalModel.CodePreconditionFailed,
+			"The blog post doesn't match the If-Match header.")), nil
+	}
+	if !conditional {
+		revision, ok = parseRevisionQuery(request)
+		if !ok {
+			return writer.Error(400), nil
+		}
+	}
+
+	// JSON patches are recognized by their media type and everything else is treated as a merge patch.
+	patchType := model.MergePatch
+	if strings.HasPrefix(strings.ToLower(middleware.Header(request, "Content-Type")), "application/json-patch+json") {
+		patchType = model.JSONPatch
+	}
+
+	response := service.Patch(caller(request), id, revision, model.Patch{Type: patchType, Document: []byte(request.Body)})
+
+	return withEntityTag(writer.Response(response), response), nil
 }
 
 func deleteBlogPost(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	response := service.Delete(request.PathParameters["id"])
-	responseBytes, _ := json.Marshal(response)
+	id := request.PathParameters["id"]
+	revision, conditional, ok := ifMatchRevision(request, id)
+	if conditional && !ok {
+		return writer.Error(412, globalModel.NewError(globalModel.CodePreconditionFailed,
+			"The blog post doesn't match the If-Match header.")), nil
+	}
+	if !conditional {
+		revision, ok = parseRevisionQuery(request)
+		if !ok {
+			return writer.Error(400), nil
+		}
+	}
 
-	return events.APIGatewayProxyResponse{
-			Body: string(responseBytes),
-			Headers: map[string]string{
-				"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-				"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-				"Access-Control-Allow-Origin":  "*",
-			},
-			StatusCode: response.StatusCode,
-		},
-		nil
+	response := service.Delete(caller(request), id, revision)
+
+	return writer.Response(response), nil
 }
 
 func getBlogPost(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
 	response := service.Get(request.PathParameters["id"])
-	responseBytes, _ := json.Marshal(response)
+	if etag := entityTag(response); etag != "" && matchesEntityTag(middleware.Header(request, "If-None-Match"), etag) {
+		return withEntityTag(events.APIGatewayProxyResponse{StatusCode: 304}, response), nil
+	}
 
-	return events.APIGatewayProxyResponse{
-			Body: string(responseBytes),
-			Headers: map[string]string{
-				"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-				"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-				"Access-Control-Allow-Origin":  "*",
-			},
-			StatusCode: response.StatusCode,
-		},
-		nil
+	return withEntityTag(writer.Response(response), response), nil
 }
 
 func getAllBlogPosts(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	response := service.GetAll()
-	responseBytes, _ := json.Marshal(response)
+	pageSize, ok := parsePageSize(request)
+	if !ok {
+		return writer.Error(400), nil
+	}
 
-	return events.APIGatewayProxyResponse{
-			Body: string(responseBytes),
-			Headers: map[string]string{
-				"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-				"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-				"Access-Control-Allow-Origin":  "*",
-			},
-			StatusCode: response.StatusCode,
-		},
-		nil
+	return writer.Response(service.GetAll(pageSize)), nil
 }
 
 func getMoreBlogPosts(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	lastID := request.QueryStringParameters["lastID"]
-
-	if strings.TrimSpace(lastID) == "" {
-		return events.APIGatewayProxyResponse{
-				Body: "The input is invalid.",
-				Headers: map[string]string{
-					"Content-Type":                 "application/text",
-					"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-					"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-					"Access-Control-Allow-Origin":  "*",
-				},
-				StatusCode: 400,
-			},
-			nil
-	}
-
-	response := service.GetMore(lastID)
-	responseBytes, _ := json.Marshal(response)
-
-	return events.APIGatewayProxyResponse{
-			Body: string(responseBytes),
-			Headers: map[string]string{
-				"Access-Control-Allow-Methods": "DELETE,GET,HEAD,OPTIONS,PATCH,POST,PUT",
-				"Access-Control-Allow-Headers": "Content-Type,X-Amz-Date,Authorization,X-Api-Key,X-Amz-Security-Token",
-				"Access-Control-Allow-Origin":  "*",
-			},
-			StatusCode: response.StatusCode,
-		},
-		nil
+	cursor := request.QueryStringParameters["cursor"]
+	pageSize, ok := parsePageSize(request)
+
+	if strings.TrimSpace(cursor) == "" || !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetMore(cursor, pageSize)), nil
+}
+
+func getAllBlogPostsByCategory(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	category := request.QueryStringParameters["category"]
+	pageSize, ok := parsePageSize(request)
+
+	if strings.TrimSpace(category) == "" || !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetAllByCategory(category, pageSize)), nil
+}
+
+func getMoreBlogPostsByCategory(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	category := request.QueryStringParameters["category"]
+	cursor := request.QueryStringParameters["cursor"]
+	pageSize, ok := parsePageSize(request)
+
+	if strings.TrimSpace(category) == "" || strings.TrimSpace(cursor) == "" || !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetMoreByCategory(category, cursor, pageSize)), nil
+}
+
+func listAuthorBlogPosts(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	authorID := request.PathParameters["id"]
+	cursor, hasCursor := request.QueryStringParameters["cursor"]
+	pageSize, ok := parsePageSize(request)
+
+	if strings.TrimSpace(authorID) == "" || (hasCursor && strings.TrimSpace(cursor) == "") || !ok {
+		return writer.Error(400), nil
+	}
+	if hasCursor {
+		return writer.Response(service.GetMoreByAuthor(authorID, cursor, pageSize)), nil
+	}
+
+	return writer.Response(service.GetAllByAuthor(authorID, pageSize)), nil
+}
+
+func getAllBlogPostsByTag(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	tag := model.NormalizeTag(request.QueryStringParameters["tag"])
+	pageSize, ok := parsePageSize(request)
+
+	if tag == "" || !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetAllByTag(tag, pageSize)), nil
+}
+
+func getMoreBlogPostsByTag(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	tag := model.NormalizeTag(request.QueryStringParameters["tag"])
+	cursor := request.QueryStringParameters["cursor"]
+	pageSize, ok := parsePageSize(request)
+
+	if tag == "" || strings.TrimSpace(cursor) == "" || !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetMoreByTag(tag, cursor, pageSize)), nil
+}
+
+func getDraft(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	response := service.GetDraft(caller(request), request.PathParameters["id"])
+	if etag := entityTag(response); etag != "" && matchesEntityTag(middleware.Header(request, "If-None-Match"), etag) {
+		return withEntityTag(events.APIGatewayProxyResponse{StatusCode: 304}, response), nil
+	}
+
+	return withEntityTag(writer.Response(response), response), nil
+}
+
+func getAllDrafts(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	pageSize, ok := parsePageSize(request)
+	if !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetAllDrafts(caller(request), pageSize)), nil
+}
+
+func getMoreDrafts(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	cursor := request.QueryStringParameters["cursor"]
+	pageSize, ok := parsePageSize(request)
+
+	if strings.TrimSpace(cursor) == "" || !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetMoreDrafts(caller(request), cursor, pageSize)), nil
+}
+
+func getAllTrash(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	pageSize, ok := parsePageSize(request)
+	if !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetAllTrash(caller(request), pageSize)), nil
+}
+
+func getMoreTrash(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	cursor := request.QueryStringParameters["cursor"]
+	pageSize, ok := parsePageSize(request)
+
+	if strings.TrimSpace(cursor) == "" || !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetMoreTrash(caller(request), cursor, pageSize)), nil
+}
+
+func getTags(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return writer.Response(service.GetTags()), nil
+}
+
+func restoreBlogPost(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	return writer.Response(service.Restore(caller(request), id)), nil
+}
+
+func getRevisions(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	return writer.Response(service.GetRevisions(id)), nil
+}
+
+func getRevision(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	revision, ok := parseRevisionParameter(request)
+	if !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetRevision(id, revision)), nil
+}
+
+func getDraftRevisions(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	return writer.Response(service.GetDraftRevisions(caller(request), id)), nil
+}
+
+func getDraftRevision(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	revision, ok := parseRevisionParameter(request)
+	if !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetDraftRevision(caller(request), id, revision)), nil
+}
+
+func restoreRevision(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	revision, ok := parseRevisionParameter(request)
+	if !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.RestoreRevision(caller(request), id, revision)), nil
+}
+
+func getDiff(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	from, to, ok := parseDiffQuery(request)
+	if !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetDiff(id, from, to)), nil
+}
+
+func getDraftDiff(service generic.Service, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	id := request.PathParameters["id"]
+	from, to, ok := parseDiffQuery(request)
+	if !ok {
+		return writer.Error(400), nil
+	}
+
+	return writer.Response(service.GetDraftDiff(caller(request), id, from, to)), nil
+}
+
+// parseRevisionParameter parses the "revision" path parameter, which must be a positive number.
+func parseRevisionParameter(request events.APIGatewayProxyRequest) (int64, bool) {
+	revision, err := strconv.ParseInt(request.PathParameters["revision"], 10, 64)
+
+	return revision, err == nil && revision > 0
+}
+
+// parseDiffQuery parses the required "from" and "to" query string parameters, which are the revisions to compare.
+func parseDiffQuery(request events.APIGatewayProxyRequest) (int64, int64, bool) {
+	from, err := strconv.ParseInt(request.QueryStringParameters["from"], 10, 64)
+	if err != nil || from <= 0 {
+		return 0, 0, false
+	}
+	to, err := strconv.ParseInt(request.QueryStringParameters["to"], 10, 64)
+	if err != nil || to <= 0 {
+		return 0, 0, false
+	}
+
+	return from, to, true
+}
+
+// parseRevisionQuery parses the optional "revision" query string parameter. If it's missing, it returns 0.
+func parseRevisionQuery(request events.APIGatewayProxyRequest) (int64, bool) {
+	value := request.QueryStringParameters["revision"]
+	if value == "" {
+		return 0, true
+	}
+
+	revision, err := strconv.ParseInt(value, 10, 64)
+
+	return revision, err == nil && revision > 0
+}
+
+// ifMatchRevision returns the revision of a blog post that the "If-Match" header expects. The second value reports if
+// the header was given at all, and the third if it refers to a revision of the blog post. A "*" matches every
+// revision, so it returns 0. Besides entity tags, a plain revision number is also accepted.
+func ifMatchRevision(request events.APIGatewayProxyRequest, id string) (int64, bool, bool) {
+	value := strings.TrimSpace(middleware.Header(request, "If-Match"))
+	if value == "" {
+		return 0, false, true
+	}
+	if value == "*" {
+		return 0, true, true
+	}
+
+	for _, tag := range strings.Split(value, ",") {
+		if revision, ok := parseEntityTag(tag, id); ok {
+			return revision, true, true
+		}
+	}
+
+	return 0, true, false
+}
+
+// withEntityTag adds the entity tag of the blog post in the response of an operation to an API Gateway response.
+func withEntityTag(result events.APIGatewayProxyResponse, response globalModel.Response) events.APIGatewayProxyResponse {
+	if etag := entityTag(response); etag != "" {
+		if result.Headers == nil {
+			result.Headers = map[string]string{}
+		}
+		result.Headers["ETag"] = etag
+	}
+
+	return result
+}
+
+// entityTag returns the entity tag of the blog post in a successful response, or an empty string if there is none.
+// The tag is derived from the id and the revision of the blog post, so it changes with every update.
+func entityTag(response globalModel.Response) string {
+	post, ok := response.Entity.(model.BlogPost)
+	if response.StatusCode != 200 || !ok {
+		return ""
+	}
+
+	return formatEntityTag(post.ID, post.Revision)
+}
+
+// formatEntityTag returns the entity tag of a blog post's revision, e.g. "3-1a2b3c4d5e6f7a8b". The second part is a
+// hash of the id, so that the tags of different blog posts don't match.
+func formatEntityTag(id string, revision int64) string {
+	hash := sha256.Sum256([]byte(id))
+
+	return fmt.Sprintf("\"%d-%x\"", revision, hash[:8])
+}
+
+// parseEntityTag returns the revision of an entity tag of the blog post. A plain revision number is also accepted.
+func parseEntityTag(tag string, id string) (int64, bool) {
+	value := strings.Trim(strings.TrimPrefix(strings.TrimSpace(tag), "W/"), "\"")
+	if revision, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return revision, revision > 0
+	}
+
+	separator := strings.Index(value, "-")
+	if separator < 0 {
+		return 0, false
+	}
+	revision, err := strconv.ParseInt(value[:separator], 10, 64)
+	if err != nil || revision <= 0 || formatEntityTag(id, revision) != "\""+value+"\"" {
+		return 0, false
+	}
+
+	return revision, true
+}
+
+// matchesEntityTag checks if an "If-None-Match" header matches an entity tag. Weak tags are compared like strong ones.
+func matchesEntityTag(value string, etag string) bool {
+	if strings.TrimSpace(value) == "*" {
+		return true
+	}
+	for _, tag := range strings.Split(value, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(tag), "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parsePageSize parses the optional "pageSize" query string parameter. If it's missing, it returns 0.
+func parsePageSize(request events.APIGatewayProxyRequest) (int64, bool) {
+	value := request.QueryStringParameters["pageSize"]
+	if value == "" {
+		return 0, true
+	}
+
+	pageSize, err := strconv.ParseInt(value, 10, 64)
+
+	return pageSize, err == nil
+}
+
+// caller returns the identity of the caller of a request, as authenticated by the Cognito authorizer of the API
+// Gateway.
+func caller(request events.APIGatewayProxyRequest) auth.Identity {
+	identity, _ := auth.FromRequest(request)
+
+	return identity
 }
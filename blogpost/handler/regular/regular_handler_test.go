@@ -3,15 +3,18 @@ package regular
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/printezisn/serverless-blog-back/blogpost/service/mocks"
 
 	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/global/auth"
 
 	globalModel "github.com/printezisn/serverless-blog-back/global/model"
 
 	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/mock"
 )
 
 // TestHandleCreateWithInvalidInput tests that the POST "/posts" request returns the correct response when the input
@@ -20,7 +23,8 @@ func TestHandleCreateWithInvalidInput(t *testing.T) {
 	service := new(mocks.Service)
 	handler := New(service)
 
-	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "PUT", Body: "error"}
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "PUT",
+		RequestContext: authenticatedContext(), Body: "error"}
 	response, _ := handler.Handle(request)
 
 	if response.StatusCode != 400 {
@@ -36,12 +40,13 @@ func TestHandleCreateWithSuccess(t *testing.T) {
 	post := model.BlogPost{ID: "id"}
 	postBytes, _ := json.Marshal(post)
 	postJSON := string(postBytes)
-	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "PUT", Body: postJSON}
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "PUT",
+		RequestContext: authenticatedContext(), Body: postJSON}
 	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
 	expectedResponseBytes, _ := json.Marshal(expectedResponse)
 	expectedResponseJSON := string(expectedResponseBytes)
 
-	service.On("Create", post).Return(expectedResponse)
+	service.On("Create", editor, post).Return(expectedResponse)
 
 	actualResponse, _ := handler.Handle(request)
 
@@ -59,7 +64,8 @@ func TestHandleUpdateWithInvalidInput(t *testing.T) {
 	service := new(mocks.Service)
 	handler := New(service)
 
-	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "POST", Body: "error"}
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "POST",
+		RequestContext: authenticatedContext(), Body: "error"}
 	response, _ := handler.Handle(request)
 
 	if response.StatusCode != 400 {
@@ -75,12 +81,13 @@ func TestHandleUpdateWithSuccess(t *testing.T) {
 	post := model.BlogPost{ID: "id"}
 	postBytes, _ := json.Marshal(post)
 	postJSON := string(postBytes)
-	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "POST", Body: postJSON}
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "POST",
+		RequestContext: authenticatedContext(), Body: postJSON}
 	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
 	expectedResponseBytes, _ := json.Marshal(expectedResponse)
 	expectedResponseJSON := string(expectedResponseBytes)
 
-	service.On("Update", post).Return(expectedResponse)
+	service.On("Update", editor, post).Return(expectedResponse)
 
 	actualResponse, _ := handler.Handle(request)
 
@@ -97,12 +104,13 @@ func TestHandleDeleteWithSuccess(t *testing.T) {
 	service := new(mocks.Service)
 	handler := New(service)
 
-	request := events.APIGatewayProxyRequest{Path: "/posts/id", HTTPMethod: "DELETE", PathParameters: map[string]string{"id": "id"}}
+	request := events.APIGatewayProxyRequest{Path: "/posts/id", HTTPMethod: "DELETE",
+		RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"}}
 	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
 	expectedResponseBytes, _ := json.Marshal(expectedResponse)
 	expectedResponseJSON := string(expectedResponseBytes)
 
-	service.On("Delete", "id").Return(expectedResponse)
+	service.On("Delete", editor, "id", int64(0)).Return(expectedResponse)
 
 	actualResponse, _ := handler.Handle(request)
 
@@ -114,6 +122,308 @@ func TestHandleDeleteWithSuccess(t *testing.T) {
 	}
 }
 
+// TestHandleDeleteWithExpectedRevision tests that the DELETE "/posts/{id+}" request passes the expected revision from
+// the "If-Match" header or the "revision" query string parameter, and that a stale revision returns 409 with the
+// current blog post either way.
+func TestHandleDeleteWithExpectedRevision(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/posts/id", HTTPMethod: "DELETE",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"},
+			Headers: map[string]string{"if-match": formatEntityTag("id", 3)}},
+		{Path: "/posts/id", HTTPMethod: "DELETE",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"},
+			Headers: map[string]string{"If-Match": "\"3\""}},
+		{Path: "/posts/id", HTTPMethod: "DELETE",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"},
+			QueryStringParameters: map[string]string{"revision": "3"}},
+	}
+	currentPost := model.BlogPost{ID: "id", Revision: 4}
+
+	service.On("Delete", editor, "id", int64(3)).Return(globalModel.Response{Entity: currentPost, StatusCode: 409})
+
+	for _, request := range requests {
+		actualResponse, _ := handler.Handle(request)
+
+		if actualResponse.StatusCode != 409 {
+			t.Errorf("The status code was expected to be 409, but it was %d.", actualResponse.StatusCode)
+		}
+		var body struct {
+			Entity model.BlogPost `json:"entity"`
+		}
+		if err := json.Unmarshal([]byte(actualResponse.Body), &body); err != nil || !body.Entity.Equal(currentPost) {
+			t.Error("The body was expected to have the current post, but it was ", actualResponse.Body)
+		}
+	}
+	service.AssertNumberOfCalls(t, "Delete", 3)
+}
+
+// TestHandleDeleteWithInvalidRevision tests that the DELETE "/posts/{id+}" request returns the correct response when
+// the expected revision is invalid.
+func TestHandleDeleteWithInvalidRevision(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/posts/id", HTTPMethod: "DELETE",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"},
+			Headers: map[string]string{"If-Match": "abc"}},
+		{Path: "/posts/id", HTTPMethod: "DELETE",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"},
+			Headers: map[string]string{"If-Match": formatEntityTag("other", 3)}},
+		{Path: "/posts/id", HTTPMethod: "DELETE",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"},
+			QueryStringParameters: map[string]string{"revision": "abc"}},
+	}
+	expectedStatusCodes := []int{412, 412, 400}
+
+	for i, request := range requests {
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != expectedStatusCodes[i] {
+			t.Errorf("The status code was expected to be %d, but it was %d.", expectedStatusCodes[i], response.StatusCode)
+		}
+	}
+	service.AssertNotCalled(t, "Delete")
+}
+
+// TestHandleGetWithEntityTag tests that the GET "/posts/{id+}" request returns the entity tag of the blog post.
+func TestHandleGetWithEntityTag(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/posts/id", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id"}}
+	post := model.BlogPost{ID: "id", Revision: 3}
+
+	service.On("Get", "id").Return(globalModel.Response{Entity: post, StatusCode: 200})
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if response.Headers["ETag"] != formatEntityTag("id", 3) {
+		t.Errorf("The entity tag was expected to be %s, but it was %s.", formatEntityTag("id", 3), response.Headers["ETag"])
+	}
+	if formatEntityTag("id", 3) == formatEntityTag("id", 4) || formatEntityTag("id", 3) == formatEntityTag("other", 3) {
+		t.Error("The entity tags of different revisions or blog posts were expected to differ.")
+	}
+}
+
+// TestHandleGetWithIfNoneMatch tests that the GET "/posts/{id+}" and "/drafts/{id}" requests return 304 when the
+// "If-None-Match" header matches the entity tag of the blog post.
+func TestHandleGetWithIfNoneMatch(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	etag := formatEntityTag("id", 3)
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/posts/id", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id"},
+			Headers: map[string]string{"If-None-Match": etag}},
+		{Path: "/posts/id", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id"},
+			Headers: map[string]string{"if-none-match": "\"other\", W/" + etag}},
+		{Path: "/drafts/id", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id"},
+			Headers: map[string]string{"If-None-Match": etag}, RequestContext: authenticatedContext()},
+		{Path: "/posts/id", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id"},
+			Headers: map[string]string{"If-None-Match": formatEntityTag("id", 2)}},
+	}
+	expectedStatusCodes := []int{304, 304, 304, 200}
+	response := globalModel.Response{Entity: model.BlogPost{ID: "id", Revision: 3}, StatusCode: 200}
+
+	service.On("Get", "id").Return(response)
+	service.On("GetDraft", editor, "id").Return(response)
+
+	for i, request := range requests {
+		actualResponse, _ := handler.Handle(request)
+
+		if actualResponse.StatusCode != expectedStatusCodes[i] {
+			t.Errorf("The status code was expected to be %d, but it was %d.", expectedStatusCodes[i], actualResponse.StatusCode)
+		}
+		if actualResponse.StatusCode == 304 && actualResponse.Body != "" {
+			t.Error("The body was expected to be empty, but it was ", actualResponse.Body)
+		}
+		if actualResponse.Headers["ETag"] != etag {
+			t.Errorf("The entity tag was expected to be %s, but it was %s.", etag, actualResponse.Headers["ETag"])
+		}
+	}
+}
+
+// TestHandleUpdateWithIfMatch tests that the POST "/posts" request takes the revision from the "If-Match" header when
+// the body doesn't have one.
+func TestHandleUpdateWithIfMatch(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	post := model.BlogPost{ID: "id", Title: "title"}
+	postBytes, _ := json.Marshal(post)
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "POST",
+		RequestContext: authenticatedContext(), Body: string(postBytes),
+		Headers: map[string]string{"If-Match": formatEntityTag("id", 3)}}
+	expectedPost := post
+	expectedPost.Revision = 3
+	updatedPost := expectedPost
+	updatedPost.Revision = 4
+
+	service.On("Update", editor, expectedPost).Return(globalModel.Response{Entity: updatedPost, StatusCode: 200})
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if response.Headers["ETag"] != formatEntityTag("id", 4) {
+		t.Errorf("The entity tag was expected to be %s, but it was %s.", formatEntityTag("id", 4), response.Headers["ETag"])
+	}
+}
+
+// TestHandleUpdateWithIfMatchAny tests that the POST "/posts" request updates the current revision when the
+// "If-Match" header is "*" and the body doesn't have a revision.
+func TestHandleUpdateWithIfMatchAny(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	post := model.BlogPost{ID: "id", Title: "title"}
+	postBytes, _ := json.Marshal(post)
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "POST",
+		RequestContext: authenticatedContext(), Body: string(postBytes), Headers: map[string]string{"If-Match": "*"}}
+	currentPost := model.BlogPost{ID: "id", Title: "old title", Revision: 3}
+	expectedPost := post
+	expectedPost.Revision = 3
+	updatedPost := expectedPost
+	updatedPost.Revision = 4
+
+	service.On("GetDraft", editor, "id").Return(globalModel.Response{Entity: currentPost, StatusCode: 200})
+	service.On("Update", editor, expectedPost).Return(globalModel.Response{Entity: updatedPost, StatusCode: 200})
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if response.Headers["ETag"] != formatEntityTag("id", 4) {
+		t.Errorf("The entity tag was expected to be %s, but it was %s.", formatEntityTag("id", 4), response.Headers["ETag"])
+	}
+}
+
+// TestHandleUpdateWithIfMatchAnyAndMissingPost tests that the POST "/posts" request returns 404 when the "If-Match"
+// header is "*" and the blog post doesn't exist.
+func TestHandleUpdateWithIfMatchAnyAndMissingPost(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "POST",
+		RequestContext: authenticatedContext(), Body: `{"id":"id"}`, Headers: map[string]string{"If-Match": "*"}}
+
+	service.On("GetDraft", editor, "id").Return(globalModel.Response{Entity: model.BlogPost{}, StatusCode: 404})
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+	service.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestHandleUpdateWithIfMatchMismatch tests that the POST "/posts" request returns 412 when the "If-Match" header
+// doesn't match the blog post.
+func TestHandleUpdateWithIfMatchMismatch(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	post := model.BlogPost{ID: "id", Title: "title", Revision: 2}
+	postBytes, _ := json.Marshal(post)
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/posts", HTTPMethod: "POST", RequestContext: authenticatedContext(), Body: string(postBytes),
+			Headers: map[string]string{"If-Match": formatEntityTag("id", 3)}},
+		{Path: "/posts", HTTPMethod: "POST", RequestContext: authenticatedContext(), Body: string(postBytes),
+			Headers: map[string]string{"If-Match": formatEntityTag("other", 2)}},
+		{Path: "/posts", HTTPMethod: "POST", RequestContext: authenticatedContext(), Body: string(postBytes),
+			Headers: map[string]string{"If-Match": formatEntityTag("id", 2)}},
+	}
+
+	service.On("Update", editor, post).Return(globalModel.Response{Entity: post, StatusCode: 409})
+
+	for _, request := range requests {
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != 412 {
+			t.Errorf("The status code was expected to be 412, but it was %d.", response.StatusCode)
+		}
+	}
+	service.AssertNumberOfCalls(t, "Update", 1)
+}
+
+// TestHandlePatch tests that the PATCH "/posts/{id+}" request passes the patch and its type to the service.
+func TestHandlePatch(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/posts/id", HTTPMethod: "PATCH",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"},
+			Body: `{"title":"title"}`, QueryStringParameters: map[string]string{"revision": "3"}},
+		{Path: "/posts/id", HTTPMethod: "PATCH",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"},
+			Body:    `[{"op":"remove","path":"/body"}]`,
+			Headers: map[string]string{"Content-Type": "application/json-patch+json", "If-Match": formatEntityTag("id", 3)}},
+	}
+	patches := []model.Patch{
+		{Type: model.MergePatch, Document: []byte(`{"title":"title"}`)},
+		{Type: model.JSONPatch, Document: []byte(`[{"op":"remove","path":"/body"}]`)},
+	}
+	post := model.BlogPost{ID: "id", Revision: 4}
+
+	for i := range requests {
+		service.On("Patch", editor, "id", int64(3), patches[i]).Return(globalModel.Response{Entity: post, StatusCode: 200})
+
+		response, _ := handler.Handle(requests[i])
+
+		if response.StatusCode != 200 {
+			t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+		}
+		if response.Headers["ETag"] != formatEntityTag("id", 4) {
+			t.Errorf("The entity tag was expected to be %s, but it was %s.", formatEntityTag("id", 4), response.Headers["ETag"])
+		}
+	}
+	service.AssertNumberOfCalls(t, "Patch", 2)
+}
+
+// TestHandlePatchWithConflict tests that the PATCH "/posts/{id+}" request returns 412 when the "If-Match" header is
+// for another blog post, 409 when its revision is stale, and that unsupported paths are rejected.
+func TestHandlePatchWithConflict(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/posts/id", HTTPMethod: "PATCH",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"}, Body: `{}`,
+			Headers: map[string]string{"If-Match": formatEntityTag("other", 3)}},
+		{Path: "/posts/id", HTTPMethod: "PATCH",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"}, Body: `{}`,
+			Headers: map[string]string{"If-Match": formatEntityTag("id", 3)}},
+		{Path: "/posts/id", HTTPMethod: "PATCH",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id"}, Body: `{}`,
+			QueryStringParameters: map[string]string{"revision": "abc"}},
+		{Path: "/posts/id/revisions", HTTPMethod: "PATCH",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id/revisions"},
+			Body: `{}`},
+	}
+	expectedStatusCodes := []int{412, 409, 400, 405}
+
+	service.On("Patch", editor, "id", int64(3), mock.Anything).Return(globalModel.Response{Entity: "response", StatusCode: 409})
+
+	for i, request := range requests {
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != expectedStatusCodes[i] {
+			t.Errorf("The status code was expected to be %d, but it was %d.", expectedStatusCodes[i], response.StatusCode)
+		}
+	}
+	service.AssertNumberOfCalls(t, "Patch", 1)
+}
+
 // TestHandleGetWithSuccess tests that the GET "/posts/{id+}" request returns the correct response when the operation is successful.
 func TestHandleGetWithSuccess(t *testing.T) {
 	service := new(mocks.Service)
@@ -136,6 +446,26 @@ func TestHandleGetWithSuccess(t *testing.T) {
 	}
 }
 
+// TestHandleGetWithoutKey tests that the GET "/posts/{id}" request doesn't return the internal key of the blog post.
+func TestHandleGetWithoutKey(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/posts/id", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id"}}
+	post := model.BlogPost{ID: "id", Key: "01ARZ3NDEKTSV4RRFFQ69G5FAV", Revision: 1}
+
+	service.On("Get", "id").Return(globalModel.Response{Entity: post, StatusCode: 200})
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != 200 {
+		t.Errorf("The status code was expected to be %d, but it was %d.", 200, actualResponse.StatusCode)
+	}
+	if strings.Contains(actualResponse.Body, post.Key) || strings.Contains(actualResponse.Body, `"key"`) {
+		t.Error("The body was expected not to have the key, but it was ", actualResponse.Body)
+	}
+}
+
 // TestHandleGetAllWithSuccess tests that the GET "/posts" request returns the correct response when the operation is successful.
 func TestHandleGetAllWithSuccess(t *testing.T) {
 	service := new(mocks.Service)
@@ -146,7 +476,7 @@ func TestHandleGetAllWithSuccess(t *testing.T) {
 	expectedResponseBytes, _ := json.Marshal(expectedResponse)
 	expectedResponseJSON := string(expectedResponseBytes)
 
-	service.On("GetAll").Return(expectedResponse)
+	service.On("GetAll", int64(0)).Return(expectedResponse)
 
 	actualResponse, _ := handler.Handle(request)
 
@@ -158,21 +488,57 @@ func TestHandleGetAllWithSuccess(t *testing.T) {
 	}
 }
 
-// TestHandleGetMoreWithSuccess tests that the GET "/posts?lastID=..." request returns the correct
+// TestHandleGetAllWithPageSize tests that the GET "/posts?pageSize=..." request passes the page size to the service.
+func TestHandleGetAllWithPageSize(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	queryStringParameters := map[string]string{"pageSize": "5"}
+	request := events.APIGatewayProxyRequest{Path: "/posts?pageSize=5", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+
+	service.On("GetAll", int64(5)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+}
+
+// TestHandleGetAllWithInvalidPageSize tests that the GET "/posts?pageSize=..." request returns the correct response
+// when the page size is not a number.
+func TestHandleGetAllWithInvalidPageSize(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	queryStringParameters := map[string]string{"pageSize": "many"}
+	request := events.APIGatewayProxyRequest{Path: "/posts?pageSize=many", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters}
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestHandleGetMoreWithSuccess tests that the GET "/posts?cursor=..." request returns the correct
 // response when the operation is successful.
 func TestHandleGetMoreWithSuccess(t *testing.T) {
 	service := new(mocks.Service)
 	handler := New(service)
 
-	lastID := "lastID"
-	path := fmt.Sprintf("/posts?lastID=%s", lastID)
-	queryStringParameters := map[string]string{"lastID": lastID}
+	cursor := "cursor"
+	path := fmt.Sprintf("/posts?cursor=%s&pageSize=5", cursor)
+	queryStringParameters := map[string]string{"cursor": cursor, "pageSize": "5"}
 	request := events.APIGatewayProxyRequest{Path: path, HTTPMethod: "GET", QueryStringParameters: queryStringParameters}
 	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
 	expectedResponseBytes, _ := json.Marshal(expectedResponse)
 	expectedResponseJSON := string(expectedResponseBytes)
 
-	service.On("GetMore", lastID).Return(expectedResponse)
+	service.On("GetMore", cursor, int64(5)).Return(expectedResponse)
 
 	actualResponse, _ := handler.Handle(request)
 
@@ -184,15 +550,15 @@ func TestHandleGetMoreWithSuccess(t *testing.T) {
 	}
 }
 
-// TestHandleGetMoreWithInvalidInput tests that the GET "/posts?lastID=..." request returns the correct
+// TestHandleGetMoreWithInvalidInput tests that the GET "/posts?cursor=..." request returns the correct
 // response when the input is invalid.
 func TestHandleGetMoreWithInvalidInput(t *testing.T) {
 	service := new(mocks.Service)
 	handler := New(service)
 
-	lastID := "  "
-	path := fmt.Sprintf("/posts?lastID=%s", lastID)
-	queryStringParameters := map[string]string{"lastID": lastID}
+	cursor := "  "
+	path := fmt.Sprintf("/posts?cursor=%s", cursor)
+	queryStringParameters := map[string]string{"cursor": cursor}
 	request := events.APIGatewayProxyRequest{Path: path, HTTPMethod: "GET", QueryStringParameters: queryStringParameters}
 
 	response, _ := handler.Handle(request)
@@ -202,26 +568,162 @@ func TestHandleGetMoreWithInvalidInput(t *testing.T) {
 	}
 }
 
-// TestHandleOptions tests that the OPTIONS "/posts" request returns the correct response.
-func TestHandleOptions(t *testing.T) {
+// TestHandleGetAllByCategoryWithSuccess tests that the GET "/posts?category=..." request returns the correct
+// response when the operation is successful.
+func TestHandleGetAllByCategoryWithSuccess(t *testing.T) {
 	service := new(mocks.Service)
 	handler := New(service)
 
-	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "OPTIONS"}
+	queryStringParameters := map[string]string{"category": "category"}
+	request := events.APIGatewayProxyRequest{Path: "/posts?category=category", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetAllByCategory", "category", int64(0)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetMoreByCategoryWithSuccess tests that the GET "/posts?category=...&cursor=..." request returns the
+// correct response when the operation is successful.
+func TestHandleGetMoreByCategoryWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	queryStringParameters := map[string]string{"category": "category", "cursor": "cursor", "pageSize": "5"}
+	request := events.APIGatewayProxyRequest{Path: "/posts?category=category&cursor=cursor&pageSize=5",
+		HTTPMethod: "GET", QueryStringParameters: queryStringParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetMoreByCategory", "category", "cursor", int64(5)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetByAuthor tests that the GET "/authors/{id}/posts" request lists the blog posts of an author, with or
+// without a cursor.
+func TestHandleGetByAuthor(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+
+	service.On("GetAllByAuthor", "author", int64(0)).Return(expectedResponse)
+	service.On("GetMoreByAuthor", "author", "cursor", int64(5)).Return(expectedResponse)
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/authors/author/posts", HTTPMethod: "GET"},
+		{Path: "/authors/author/posts", HTTPMethod: "GET",
+			QueryStringParameters: map[string]string{"cursor": "cursor", "pageSize": "5"}},
+		{Path: "/authors/author/posts", HTTPMethod: "GET", QueryStringParameters: map[string]string{"cursor": " "}},
+		{Path: "/authors/author/posts", HTTPMethod: "GET", QueryStringParameters: map[string]string{"pageSize": "x"}},
+	}
+	expectedStatusCodes := []int{200, 200, 400, 400}
+
+	for i, request := range requests {
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != expectedStatusCodes[i] {
+			t.Errorf("The status code for %v was expected to be %d, but it was %d.", request.QueryStringParameters,
+				expectedStatusCodes[i], response.StatusCode)
+		}
+	}
+	service.AssertExpectations(t)
+}
+
+// TestHandleGetAllByCategoryWithInvalidInput tests that the GET "/posts?category=..." request returns the correct
+// response when the category is blank.
+func TestHandleGetAllByCategoryWithInvalidInput(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	queryStringParameters := map[string]string{"category": "  "}
+	request := events.APIGatewayProxyRequest{Path: "/posts?category=%20%20", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters}
 
 	response, _ := handler.Handle(request)
 
-	if response.StatusCode != 200 {
-		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
 	}
 }
 
-// TestInvalidRequest tests that the correct response is returned when the request is invalid.
-func TestInvalidRequest(t *testing.T) {
+// TestHandleGetAllByTagWithSuccess tests that the GET "/posts?tag=..." request normalizes the tag and returns the
+// correct response when the operation is successful.
+func TestHandleGetAllByTagWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	queryStringParameters := map[string]string{"tag": " Go "}
+	request := events.APIGatewayProxyRequest{Path: "/posts?tag=%20Go%20", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetAllByTag", "go", int64(0)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetMoreByTagWithSuccess tests that the GET "/posts?tag=...&cursor=..." request returns the correct
+// response when the operation is successful.
+func TestHandleGetMoreByTagWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	queryStringParameters := map[string]string{"tag": "go", "cursor": "cursor", "pageSize": "5"}
+	request := events.APIGatewayProxyRequest{Path: "/posts?tag=go&cursor=cursor&pageSize=5", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetMoreByTag", "go", "cursor", int64(5)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetAllByTagWithInvalidInput tests that the GET "/posts?tag=..." request returns the correct response
+// when the tag is blank.
+func TestHandleGetAllByTagWithInvalidInput(t *testing.T) {
 	service := new(mocks.Service)
 	handler := New(service)
 
-	request := events.APIGatewayProxyRequest{Path: "/", HTTPMethod: "GET"}
+	queryStringParameters := map[string]string{"tag": "  "}
+	request := events.APIGatewayProxyRequest{Path: "/posts?tag=%20%20", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters}
 
 	response, _ := handler.Handle(request)
 
@@ -229,3 +731,589 @@ func TestInvalidRequest(t *testing.T) {
 		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
 	}
 }
+
+// TestHandleGetTagsWithSuccess tests that the GET "/tags" request returns the correct response when the operation
+// is successful.
+func TestHandleGetTagsWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/tags", HTTPMethod: "GET"}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetTags").Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetAllDraftsWithSuccess tests that the GET "/drafts" request returns the correct response when the
+// caller is authenticated.
+func TestHandleGetAllDraftsWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/drafts", HTTPMethod: "GET",
+		RequestContext: authenticatedContext()}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetAllDrafts", editor, int64(0)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetMoreDraftsWithSuccess tests that the GET "/drafts?cursor=..." request returns the correct response
+// when the caller is authenticated.
+func TestHandleGetMoreDraftsWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	queryStringParameters := map[string]string{"cursor": "cursor"}
+	request := events.APIGatewayProxyRequest{Path: "/drafts?cursor=cursor", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters, RequestContext: authenticatedContext()}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetMoreDrafts", editor, "cursor", int64(0)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetDraftWithSuccess tests that the GET "/drafts/{id}" request returns the correct response when the
+// caller is authenticated.
+func TestHandleGetDraftWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id"}
+	request := events.APIGatewayProxyRequest{Path: "/drafts/id", HTTPMethod: "GET", PathParameters: pathParameters,
+		RequestContext: authenticatedContext()}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetDraft", editor, "id").Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetDraftsWithoutAuthentication tests that the GET "/drafts" request is rejected when the caller is not
+// authenticated.
+func TestHandleGetDraftsWithoutAuthentication(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/drafts", HTTPMethod: "GET"}
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 401 {
+		t.Errorf("The status code was expected to be 401, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestHandleGetRevisionsWithSuccess tests that the GET "/posts/{id}/revisions" request returns the correct response when
+// the operation is successful.
+func TestHandleGetRevisionsWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id/revisions"}
+	request := events.APIGatewayProxyRequest{Path: "/posts/id/revisions", HTTPMethod: "GET", PathParameters: pathParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetRevisions", "id").Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetRevisionWithSuccess tests that the GET "/posts/{id}/revisions/{rev}" request returns the correct response
+// when the operation is successful.
+func TestHandleGetRevisionWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id/revisions/2"}
+	request := events.APIGatewayProxyRequest{Path: "/posts/id/revisions/2", HTTPMethod: "GET", PathParameters: pathParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetRevision", "id", int64(2)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetDraftRevisionsWithSuccess tests that the GET "/drafts/{id}/revisions" request returns the correct
+// response when the caller is authenticated.
+func TestHandleGetDraftRevisionsWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id/revisions"}
+	request := events.APIGatewayProxyRequest{Path: "/drafts/id/revisions", HTTPMethod: "GET", PathParameters: pathParameters,
+		RequestContext: authenticatedContext()}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetDraftRevisions", editor, "id").Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetDraftRevisionWithSuccess tests that the GET "/drafts/{id}/revisions/{rev}" request returns the correct
+// response when the caller is authenticated.
+func TestHandleGetDraftRevisionWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id/revisions/2"}
+	request := events.APIGatewayProxyRequest{Path: "/drafts/id/revisions/2", HTTPMethod: "GET", PathParameters: pathParameters,
+		RequestContext: authenticatedContext()}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetDraftRevision", editor, "id", int64(2)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleRestoreRevisionWithSuccess tests that the POST "/posts/{id}/revisions/{rev}/restore" request returns the
+// correct response when the operation is successful.
+func TestHandleRestoreRevisionWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id/revisions/2/restore"}
+	request := events.APIGatewayProxyRequest{Path: "/posts/id/revisions/2/restore", HTTPMethod: "POST",
+		RequestContext: authenticatedContext(), PathParameters: pathParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("RestoreRevision", editor, "id", int64(2)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleRevisionWithInvalidInput tests that the revision requests return the correct response when the path is
+// invalid.
+func TestHandleRevisionWithInvalidInput(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/posts/id/revisions/x", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id/revisions/x"}},
+		{Path: "/posts/id/revisions/0", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id/revisions/0"}},
+		{Path: "/posts/id/revisions/x/restore", HTTPMethod: "POST",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id/revisions/x/restore"}},
+		{Path: "/posts/id/other", HTTPMethod: "GET", PathParameters: map[string]string{"id": "id/other"}},
+		{Path: "/posts/id/revisions/2", HTTPMethod: "POST", PathParameters: map[string]string{"id": "id/revisions/2"}},
+		{Path: "/posts/id/revisions/2/other", HTTPMethod: "POST",
+			RequestContext: authenticatedContext(), PathParameters: map[string]string{"id": "id/revisions/2/other"}},
+	}
+	expectedStatusCodes := []int{400, 400, 400, 404, 405, 404}
+	for i, request := range requests {
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != expectedStatusCodes[i] {
+			t.Errorf("The status code of %s %s was expected to be %d, but it was %d.", request.HTTPMethod, request.Path,
+				expectedStatusCodes[i], response.StatusCode)
+		}
+	}
+}
+
+// TestHandleGetDiffWithSuccess tests that the GET "/posts/{id}/diff" request returns the correct response when the
+// operation is successful.
+func TestHandleGetDiffWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id/diff"}
+	queryStringParameters := map[string]string{"from": "1", "to": "3"}
+	request := events.APIGatewayProxyRequest{Path: "/posts/id/diff", HTTPMethod: "GET", PathParameters: pathParameters,
+		QueryStringParameters: queryStringParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetDiff", "id", int64(1), int64(3)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetDraftDiffWithSuccess tests that the GET "/drafts/{id}/diff" request returns the correct response when
+// the caller is authenticated.
+func TestHandleGetDraftDiffWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id/diff"}
+	queryStringParameters := map[string]string{"from": "2", "to": "1"}
+	request := events.APIGatewayProxyRequest{Path: "/drafts/id/diff", HTTPMethod: "GET", PathParameters: pathParameters,
+		QueryStringParameters: queryStringParameters, RequestContext: authenticatedContext()}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetDraftDiff", editor, "id", int64(2), int64(1)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetDiffWithInvalidInput tests that the GET "/posts/{id}/diff" request returns the correct response when
+// the revisions are missing or invalid.
+func TestHandleGetDiffWithInvalidInput(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	for _, query := range []map[string]string{{}, {"from": "1"}, {"from": "x", "to": "2"}, {"from": "1", "to": "-2"}} {
+		request := events.APIGatewayProxyRequest{Path: "/posts/id/diff", HTTPMethod: "GET",
+			PathParameters: map[string]string{"id": "id/diff"}, QueryStringParameters: query}
+
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != 400 {
+			t.Error("The status code for ", query, " was expected to be 400, but it was ", response.StatusCode)
+		}
+	}
+}
+
+// TestHandleGetAllTrashWithSuccess tests that the GET "/trash" request returns the correct response when the caller
+// is authenticated.
+func TestHandleGetAllTrashWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/trash", HTTPMethod: "GET", RequestContext: authenticatedContext()}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetAllTrash", editor, int64(0)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetMoreTrashWithSuccess tests that the GET "/trash?cursor=..." request returns the correct response when
+// the caller is authenticated.
+func TestHandleGetMoreTrashWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	queryStringParameters := map[string]string{"cursor": "cursor", "pageSize": "5"}
+	request := events.APIGatewayProxyRequest{Path: "/trash", HTTPMethod: "GET",
+		QueryStringParameters: queryStringParameters, RequestContext: authenticatedContext()}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("GetMoreTrash", editor, "cursor", int64(5)).Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleGetTrashWithoutAuthentication tests that the GET "/trash" request is rejected when the caller is not
+// authenticated.
+func TestHandleGetTrashWithoutAuthentication(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/trash", HTTPMethod: "GET"}
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 401 {
+		t.Errorf("The status code was expected to be 401, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestHandleRestoreWithSuccess tests that the POST "/posts/{id}/restore" request returns the correct response when
+// the operation is successful.
+func TestHandleRestoreWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	pathParameters := map[string]string{"id": "id/restore"}
+	request := events.APIGatewayProxyRequest{Path: "/posts/id/restore", HTTPMethod: "POST",
+		RequestContext: authenticatedContext(), PathParameters: pathParameters}
+	expectedResponse := globalModel.Response{Entity: "response", StatusCode: 200}
+	expectedResponseBytes, _ := json.Marshal(expectedResponse)
+	expectedResponseJSON := string(expectedResponseBytes)
+
+	service.On("Restore", editor, "id").Return(expectedResponse)
+
+	actualResponse, _ := handler.Handle(request)
+
+	if actualResponse.StatusCode != expectedResponse.StatusCode {
+		t.Errorf("The status code was expected to be %d, but it was %d.", expectedResponse.StatusCode, actualResponse.StatusCode)
+	}
+	if actualResponse.Body != expectedResponseJSON {
+		t.Error("The body was expected to be ", expectedResponseJSON, " but it was ", actualResponse.Body)
+	}
+}
+
+// TestHandleOptions tests that the OPTIONS "/posts" request returns the correct response.
+func TestHandleOptions(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "OPTIONS"}
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestHandleOptionsOnEveryRoute tests that preflight requests are answered for every route with the CORS headers.
+func TestHandleOptionsOnEveryRoute(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	for _, path := range []string{"/posts/id", "/posts/id/revisions/2/restore", "/drafts/id/diff", "/trash", "/tags"} {
+		request := events.APIGatewayProxyRequest{Path: path, HTTPMethod: "OPTIONS",
+			Headers: map[string]string{"Origin": "https://blog.com", "Access-Control-Request-Method": "GET"}}
+
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != 200 {
+			t.Errorf("The status code of %s was expected to be 200, but it was %d.", path, response.StatusCode)
+		}
+		if response.Headers["Access-Control-Allow-Origin"] != "*" || response.Headers["Vary"] != "Origin" {
+			t.Errorf("The CORS headers of %s were not expected to be %v.", path, response.Headers)
+		}
+	}
+}
+
+// TestInvalidRequest tests that the correct response is returned when the request matches no route.
+func TestInvalidRequest(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	for _, path := range []string{"/", "/postsXYZ", "/posts/id/other"} {
+		request := events.APIGatewayProxyRequest{Path: path, HTTPMethod: "GET"}
+
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != 404 {
+			t.Errorf("The status code of %s was expected to be 404, but it was %d.", path, response.StatusCode)
+		}
+	}
+}
+
+// TestMethodNotAllowed tests that the correct response is returned when the method is not supported by the route.
+func TestMethodNotAllowed(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/tags", HTTPMethod: "DELETE"}
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 405 {
+		t.Errorf("The status code was expected to be 405, but it was %d.", response.StatusCode)
+	}
+	if response.Headers["Allow"] != "GET" {
+		t.Errorf("The Allow header was expected to be GET, but it was %s.", response.Headers["Allow"])
+	}
+}
+
+// TestHandleResponseHeaders tests that every response has the CORS headers, the request id and its content type.
+func TestHandleResponseHeaders(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/tags", HTTPMethod: "GET",
+		RequestContext: events.APIGatewayProxyRequestContext{RequestID: "request"}}
+
+	service.On("GetTags").Return(globalModel.Response{Entity: "response", StatusCode: 200})
+
+	response, _ := handler.Handle(request)
+
+	expectedHeaders := map[string]string{
+		"Content-Type":                "application/json",
+		"Access-Control-Allow-Origin": "*",
+		"X-Request-Id":                "request",
+	}
+	for name, value := range expectedHeaders {
+		if response.Headers[name] != value {
+			t.Errorf("The %s header was expected to be %s, but it was %s.", name, value, response.Headers[name])
+		}
+	}
+}
+
+// TestHandleWithPanic tests that a panic while handling a request results in an internal server error.
+func TestHandleWithPanic(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/tags", HTTPMethod: "GET"}
+
+	response, err := handler.Handle(request)
+
+	if err != nil {
+		t.Error("No error was expected, but there was ", err)
+	}
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestHandleWithUnsupportedContentType tests that a body that is not JSON is rejected.
+func TestHandleWithUnsupportedContentType(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	request := events.APIGatewayProxyRequest{Path: "/posts", HTTPMethod: "PUT",
+		RequestContext: authenticatedContext(), Body: "title=title",
+		Headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"}}
+
+	response, _ := handler.Handle(request)
+
+	if response.StatusCode != 415 {
+		t.Errorf("The status code was expected to be 415, but it was %d.", response.StatusCode)
+	}
+	service.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// TestHandleWithoutRole tests that the requests that change blog posts are rejected when the caller is not
+// authenticated or is only a reader.
+func TestHandleWithoutRole(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+	reader := events.APIGatewayProxyRequestContext{
+		Authorizer: map[string]interface{}{"claims": map[string]interface{}{"sub": "user"}},
+	}
+
+	requests := []events.APIGatewayProxyRequest{
+		{Path: "/posts", HTTPMethod: "PUT", Body: "{}"},
+		{Path: "/posts/id", HTTPMethod: "DELETE"},
+		{Path: "/drafts", HTTPMethod: "GET"},
+		{Path: "/posts", HTTPMethod: "PUT", Body: "{}", RequestContext: reader},
+		{Path: "/posts/id", HTTPMethod: "PATCH", Body: "{}", RequestContext: reader},
+		{Path: "/posts/id/restore", HTTPMethod: "POST", RequestContext: reader},
+		{Path: "/trash", HTTPMethod: "GET", RequestContext: reader},
+	}
+	expectedStatusCodes := []int{401, 401, 401, 403, 403, 403, 403}
+
+	for i, request := range requests {
+		response, _ := handler.Handle(request)
+
+		if response.StatusCode != expectedStatusCodes[i] {
+			t.Errorf("The status code of %s %s was expected to be %d, but it was %d.", request.HTTPMethod, request.Path,
+				expectedStatusCodes[i], response.StatusCode)
+		}
+	}
+	service.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+// editor is the identity of the caller of authenticatedContext.
+var editor = auth.Identity{Subject: "user", Groups: []string{"editor"}}
+
+// authenticatedContext returns the request context of a caller that the Cognito authorizer has authenticated.
+func authenticatedContext() events.APIGatewayProxyRequestContext {
+	return events.APIGatewayProxyRequestContext{
+		Authorizer: map[string]interface{}{"claims": map[string]interface{}{"sub": "user", "cognito:groups": "editor"}},
+	}
+}
@@ -0,0 +1,39 @@
+package scheduled
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/blogpost/service/generic"
+)
+
+// Handler handles the scheduled events that publish the blog posts whose time has come and purge the trash.
+type Handler struct {
+	service generic.Service
+}
+
+// New creates and returns a new handler instance.
+func New(service generic.Service) Handler {
+	return Handler{service: service}
+}
+
+// Handle handles scheduled events from CloudWatch. It returns an error if the blog posts could not be published or
+// purged, so that the failure shows up in the Lambda metrics.
+func (handle *Handler) Handle(event events.CloudWatchEvent) error {
+	response := handle.service.PublishScheduled()
+	if response.StatusCode != 200 {
+		return fmt.Errorf("the scheduled blog posts could not be published (status code %d)", response.StatusCode)
+	}
+
+	log.Println("Published the scheduled blog posts: ", response.Entity)
+
+	response = handle.service.PurgeTrash()
+	if response.StatusCode != 200 {
+		return fmt.Errorf("the trash could not be purged (status code %d)", response.StatusCode)
+	}
+
+	log.Println("Purged the blog posts in the trash: ", response.Entity)
+
+	return nil
+}
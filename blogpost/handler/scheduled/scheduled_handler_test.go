@@ -0,0 +1,58 @@
+package scheduled
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/blogpost/service/mocks"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// TestNew tests that the New method creates the handler properly.
+func TestNew(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	if handler.service != service {
+		t.Error("The service is not set correctly.")
+	}
+}
+
+// TestHandleWithSuccess tests that a scheduled event publishes the scheduled blog posts and purges the trash.
+func TestHandleWithSuccess(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	service.On("PublishScheduled").Return(globalModel.Response{Entity: []string{"id"}, StatusCode: 200})
+	service.On("PurgeTrash").Return(globalModel.Response{Entity: []string{"id2"}, StatusCode: 200})
+
+	if err := handler.Handle(events.CloudWatchEvent{}); err != nil {
+		t.Error("No error was expected, but there was ", err)
+	}
+	service.AssertExpectations(t)
+}
+
+// TestHandleWithError tests that a scheduled event fails when the blog posts could not be published.
+func TestHandleWithError(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	service.On("PublishScheduled").Return(globalModel.Response{Entity: []string{}, StatusCode: 500})
+
+	if err := handler.Handle(events.CloudWatchEvent{}); err == nil {
+		t.Error("An error was expected, but there wasn't.")
+	}
+}
+
+// TestHandleWithPurgeError tests that a scheduled event fails when the trash could not be purged.
+func TestHandleWithPurgeError(t *testing.T) {
+	service := new(mocks.Service)
+	handler := New(service)
+
+	service.On("PublishScheduled").Return(globalModel.Response{Entity: []string{}, StatusCode: 200})
+	service.On("PurgeTrash").Return(globalModel.Response{Entity: []string{}, StatusCode: 503})
+
+	if err := handler.Handle(events.CloudWatchEvent{}); err == nil {
+		t.Error("An error was expected, but there wasn't.")
+	}
+}
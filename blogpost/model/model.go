@@ -1,82 +1,265 @@
 package model
 
 import (
-	"log"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
 
-	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/printezisn/serverless-blog-back/global/diff"
+	"github.com/printezisn/serverless-blog-back/global/patch"
 )
 
 // BlogPost represents a blog post.
 type BlogPost struct {
-	ID                string `json:"id"`
-	Title             string `json:"title"`
-	Description       string `json:"description"`
-	Tags              string `json:"tags"`
-	Body              string `json:"body"`
-	Template          string `json:"template"`
-	Category          string `json:"category"`
-	Revision          int64  `json:"revision"`
-	CreationTimestamp int64  `json:"creationTimestamp"`
-	UpdateTimestamp   int64  `json:"updateTimestamp"`
-}
-
-// Page represents a page of blog posts.
+	ID                 string `json:"id"`
+	Key                string `json:"-" dynamodbav:"key"`
+	Title              string `json:"title"`
+	Description        string `json:"description"`
+	Tags               Tags   `json:"tags"`
+	Body               string `json:"body"`
+	Template           string `json:"template"`
+	Category           string `json:"category"`
+	AuthorID           string `json:"authorId"`
+	Revision           int64  `json:"revision"`
+	Status             Status `json:"status"`
+	PublishedTimestamp int64  `json:"publishedTimestamp"`
+	ScheduledAt        int64  `json:"scheduledAt"`
+	DeletedTimestamp   int64  `json:"deletedTimestamp"`
+	CreationTimestamp  int64  `json:"creationTimestamp"`
+	UpdateTimestamp    int64  `json:"updateTimestamp"`
+}
+
+// Status represents the stage of a blog post in its workflow. A blog post starts as a draft, is published and is
+// finally archived. Only published blog posts are visible to everyone. A blog post with any status can be moved to
+// the trash and restored from it.
+type Status string
+
+const (
+	// StatusDraft is the status of a blog post that is still being written.
+	StatusDraft Status = "draft"
+	// StatusPublished is the status of a blog post that is visible to everyone.
+	StatusPublished Status = "published"
+	// StatusArchived is the status of a blog post that is no longer visible to everyone.
+	StatusArchived Status = "archived"
+	// StatusDeleted is the status of a blog post that is in the trash. It's only set by deleting a blog post.
+	StatusDeleted Status = "deleted"
+)
+
+// CanBecome checks if a blog post with this status can be moved to another one. Keeping the same status is always
+// allowed.
+func (status Status) CanBecome(other Status) bool {
+	switch status {
+	case other:
+		return true
+	case StatusDraft:
+		return other == StatusPublished
+	case StatusPublished:
+		return other == StatusArchived
+	default:
+		return false
+	}
+}
+
+// CurrentStatus returns the status of a blog post. Blog posts from before the workflow have no status and are
+// considered published.
+func (post BlogPost) CurrentStatus() Status {
+	if post.Status == "" {
+		return StatusPublished
+	}
+
+	return post.Status
+}
+
+// IsDue checks if a blog post is a draft that is scheduled to be published at or before a timestamp.
+func (post BlogPost) IsDue(timestamp int64) bool {
+	return post.HasStatus(StatusDraft) && post.ScheduledAt > 0 && post.ScheduledAt <= timestamp
+}
+
+// HasStatus checks if a blog post has a status, the same way as CurrentStatus.
+func (post BlogPost) HasStatus(status Status) bool {
+	return post.CurrentStatus() == status
+}
+
+// Tags represents the tags of a blog post. Besides a JSON array, it can also be unmarshalled from a single string of
+// comma-separated tags, which is how tags used to be sent. Unmarshalled tags are always normalized.
+type Tags []string
+
+// TagCount represents a tag and the number of blog posts that have it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+// ParseTags parses a string of comma-separated tags.
+func ParseTags(value string) Tags {
+	return NormalizeTags(strings.Split(value, ","))
+}
+
+// NormalizeTag returns the normalized form of a tag: trimmed and in lowercase.
+func NormalizeTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeTags normalizes every tag, drops empty and duplicate ones and keeps the original order.
+func NormalizeTags(tags []string) Tags {
+	result := Tags{}
+	seen := map[string]bool{}
+	for _, tag := range tags {
+		tag = NormalizeTag(tag)
+		if tag != "" && !seen[tag] {
+			result = append(result, tag)
+			seen[tag] = true
+		}
+	}
+
+	return result
+}
+
+// UnmarshalJSON unmarshals tags from either a JSON array or a string of comma-separated tags.
+func (tags *Tags) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		*tags = ParseTags(value)
+		return nil
+	}
+
+	var values []string
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	*tags = NormalizeTags(values)
+
+	return nil
+}
+
+// Contains checks if the tags contain a tag.
+func (tags Tags) Contains(tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Equal checks if two blog posts have the same values. Missing and empty tags are considered equal.
+func (post BlogPost) Equal(other BlogPost) bool {
+	if len(post.Tags) != len(other.Tags) {
+		return false
+	}
+	for i := range post.Tags {
+		if post.Tags[i] != other.Tags[i] {
+			return false
+		}
+	}
+
+	post.Tags, other.Tags = nil, nil
+
+	return reflect.DeepEqual(post, other)
+}
+
+// Page represents a page of blog posts. The cursor is used to fetch the next page and is empty if there are no more
+// blog posts.
 type Page struct {
-	Posts  []BlogPost `json:"posts"`
-	Cursor string     `json:"cursor"`
-}
-
-// Validate checks if a BlogPost instance is valid and returns an error. If it's valid, it returns nil.
-func (post BlogPost) Validate() []string {
-	err := validation.ValidateStruct(
-		&post,
-		validation.Field(
-			&post.ID,
-			validation.Required.Error("The id is required."),
-			validation.Length(0, 250).Error("The id may have up to 250 characters.")),
-		validation.Field(
-			&post.Title,
-			validation.Required.Error("The title is required."),
-			validation.Length(0, 250).Error("The title may have up to 250 characters.")),
-		validation.Field(
-			&post.Description,
-			validation.Required.Error("The description is required."),
-			validation.Length(0, 250).Error("The description may have up tp 250 characters.")),
-		validation.Field(
-			&post.Tags,
-			validation.Required.Error("The tags are required."),
-			validation.Length(0, 250).Error("The tags may have up tp 250 characters.")),
-		validation.Field(
-			&post.Body,
-			validation.Required.Error("The body is required.")),
-		validation.Field(
-			&post.Template,
-			validation.Required.Error("The template is required."),
-			validation.Length(0, 50).Error("The template may have up tp 50 characters.")),
-		validation.Field(
-			&post.Category,
-			validation.Required.Error("The category is required."),
-			validation.Length(0, 250).Error("The category may have up tp 250 characters.")),
-		validation.Field(
-			&post.Revision,
-			validation.Required.Error("The revision is required.")))
-
-	if err == nil {
-		return []string{}
-	}
-
-	validationErrors, ok := err.(validation.Errors)
-	if !ok {
-		log.Fatal("An unexpected error occurred while validating a model: ", err)
-		return []string{"An unexpected error occurred."}
-	}
-
-	result := make([]string, len(validationErrors))
-	i := 0
-	for _, err = range validationErrors {
-		result[i] = err.Error()
-		i++
+	Posts   []BlogPost `json:"posts"`
+	Cursor  string     `json:"cursor"`
+	HasMore bool       `json:"hasMore"`
+}
+
+// Diff represents the changes of a blog post between two revisions. The body changes are also given as a unified
+// diff.
+type Diff struct {
+	ID       string        `json:"id"`
+	From     int64         `json:"from"`
+	To       int64         `json:"to"`
+	Changes  []FieldChange `json:"changes"`
+	BodyDiff string        `json:"bodyDiff"`
+}
+
+// FieldChange represents the old and the new value of a field of a blog post.
+type FieldChange struct {
+	Field string      `json:"field"`
+	From  interface{} `json:"from"`
+	To    interface{} `json:"to"`
+}
+
+// Compare returns the changes between two revisions of a blog post, field by field. The id, the revision and the
+// creation and update timestamps are not compared.
+func Compare(from BlogPost, to BlogPost) Diff {
+	result := Diff{ID: to.ID, From: from.Revision, To: to.Revision, Changes: []FieldChange{}}
+	addChange := func(field string, fromValue interface{}, toValue interface{}) {
+		if !reflect.DeepEqual(fromValue, toValue) {
+			result.Changes = append(result.Changes, FieldChange{Field: field, From: fromValue, To: toValue})
+		}
 	}
 
+	fromTags, toTags := from.Tags, to.Tags
+	if fromTags == nil {
+		fromTags = Tags{}
+	}
+	if toTags == nil {
+		toTags = Tags{}
+	}
+
+	addChange("title", from.Title, to.Title)
+	addChange("description", from.Description, to.Description)
+	addChange("tags", fromTags, toTags)
+	addChange("body", from.Body, to.Body)
+	addChange("template", from.Template, to.Template)
+	addChange("category", from.Category, to.Category)
+	addChange("status", from.CurrentStatus(), to.CurrentStatus())
+	addChange("publishedTimestamp", from.PublishedTimestamp, to.PublishedTimestamp)
+	addChange("scheduledAt", from.ScheduledAt, to.ScheduledAt)
+
+	result.BodyDiff = diff.Unified("revision "+strconv.FormatInt(from.Revision, 10),
+		"revision "+strconv.FormatInt(to.Revision, 10), from.Body, to.Body)
+
 	return result
 }
+
+// PatchType represents the format of a patch document.
+type PatchType string
+
+const (
+	// MergePatch is the format of a JSON merge patch (RFC 7396).
+	MergePatch PatchType = "merge"
+	// JSONPatch is the format of a JSON patch (RFC 6902).
+	JSONPatch PatchType = "json"
+)
+
+// Patch represents a patch document that changes some of the fields of a blog post.
+type Patch struct {
+	Type     PatchType
+	Document []byte
+}
+
+// Apply applies the patch to the JSON form of a blog post and returns the patched blog post.
+func (p Patch) Apply(post BlogPost) (BlogPost, error) {
+	document, err := json.Marshal(post)
+	if err != nil {
+		return BlogPost{}, err
+	}
+
+	if p.Type == JSONPatch {
+		document, err = patch.Apply(document, p.Document)
+	} else {
+		document, err = patch.Merge(document, p.Document)
+	}
+	if err != nil {
+		return BlogPost{}, err
+	}
+
+	var result BlogPost
+	if err := json.Unmarshal(document, &result); err != nil {
+		return BlogPost{}, fmt.Errorf("%w: %v", patch.ErrInvalid, err)
+	}
+
+	return result, nil
+}
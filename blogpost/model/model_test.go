@@ -1,7 +1,12 @@
 package model
 
 import (
+	"encoding/json"
+	"reflect"
+	"strings"
 	"testing"
+
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
 )
 
 // TestValidateWithRequiredErrors tests that Validate returns errors for required fields.
@@ -14,13 +19,13 @@ func TestValidateWithRequiredErrors(t *testing.T) {
 		{BlogPost{ID: "test_id"}, true},
 		{BlogPost{ID: "test_id", Title: "test_title"}, true},
 		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_description"}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_description", Tags: "test_tags"}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: "test_tags", Revision: 1}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: "test_tags", Revision: 1,
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_description", Tags: Tags{"test_tags"}}, true},
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: Tags{"test_tags"}, Revision: 1}, true},
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: Tags{"test_tags"}, Revision: 1,
 			Body: "test_body"}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: "test_tags", Revision: 1,
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: Tags{"test_tags"}, Revision: 1,
 			Body: "test_body", Template: "test_template"}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: "test_tags", Revision: 1,
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: Tags{"test_tags"}, Revision: 1,
 			Body: "test_body", Template: "test_template", Category: "test_category"}, false},
 	}
 
@@ -46,19 +51,22 @@ func TestValidateWithLengthErrors(t *testing.T) {
 		hasErrors bool
 	}{
 
-		{BlogPost{ID: longStr, Title: "test_title", Description: "test_descr", Tags: "test_tags",
+		{BlogPost{ID: longStr, Title: "test_title", Description: "test_descr", Tags: Tags{"test_tags"},
+			Body: "test_body", Template: "test_template", Category: "test_category", Revision: 1}, true},
+		{BlogPost{ID: "test_str", Title: longStr, Description: "test_descr", Tags: Tags{"test_tags"},
 			Body: "test_body", Template: "test_template", Category: "test_category", Revision: 1}, true},
-		{BlogPost{ID: "test_str", Title: longStr, Description: "test_descr", Tags: "test_tags",
+		{BlogPost{ID: "test_id", Title: "test_title", Description: longStr, Tags: Tags{"test_tags"},
 			Body: "test_body", Template: "test_template", Category: "test_category", Revision: 1}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: longStr, Tags: "test_tags",
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: Tags{longStr},
 			Body: "test_body", Template: "test_template", Category: "test_category", Revision: 1}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: longStr,
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr",
+			Tags: Tags{"t1", "t2", "t3", "t4", "t5", "t6", "t7", "t8", "t9", "t10", "t11"},
 			Body: "test_body", Template: "test_template", Category: "test_category", Revision: 1}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: "test_tags",
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: Tags{"test_tags"},
 			Body: "test_body", Template: longStr, Category: "test_category", Revision: 1}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: "test_tags",
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: Tags{"test_tags"},
 			Body: "test_body", Template: "test_template", Category: longStr, Revision: 1}, true},
-		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: "test_tags",
+		{BlogPost{ID: "test_id", Title: "test_title", Description: "test_descr", Tags: Tags{"test_tags"},
 			Body: "test_body", Template: "test_template", Category: "test_category", Revision: 1}, false},
 	}
 
@@ -71,3 +79,181 @@ func TestValidateWithLengthErrors(t *testing.T) {
 		}
 	}
 }
+
+// TestValidateWithFieldErrors tests that the errors of Validate have the codes and the JSON names of their fields.
+func TestValidateWithFieldErrors(t *testing.T) {
+	post := BlogPost{ID: "test_id", Description: "test_descr", Tags: Tags{"test_tags", strings.Repeat("a", 51)},
+		Body: "test_body", Template: "test_template", Category: "test_category", Revision: 1, Status: "other"}
+	expectedErrors := []globalModel.Error{
+		{Code: globalModel.CodeNotAllowed, Field: "status",
+			Message: "The status must be one of draft, published or archived."},
+		{Code: globalModel.CodeTooLong, Field: "tags.1", Message: "Each tag may have up to 50 characters."},
+		{Code: globalModel.CodeRequired, Field: "title", Message: "The title is required."},
+	}
+
+	errs := post.Validate()
+
+	if !reflect.DeepEqual(errs, expectedErrors) {
+		t.Errorf("The errors were expected to be %v, but they were %v.", expectedErrors, errs)
+	}
+}
+
+// TestTagsUnmarshalJSON tests that tags can be read both as a list and as a comma-separated string, and that they
+// are normalized.
+func TestTagsUnmarshalJSON(t *testing.T) {
+	testCases := []struct {
+		json         string
+		expectedTags Tags
+	}{
+		{`{"tags": ["Go", " serverless ", "go", ""]}`, Tags{"go", "serverless"}},
+		{`{"tags": "Go, serverless,,go"}`, Tags{"go", "serverless"}},
+		{`{"tags": ""}`, Tags{}},
+		{`{"tags": []}`, Tags{}},
+		{`{"tags": null}`, nil},
+		{`{}`, nil},
+	}
+
+	for _, testCase := range testCases {
+		var post BlogPost
+		if err := json.Unmarshal([]byte(testCase.json), &post); err != nil {
+			t.Error("No error was expected for ", testCase.json, " but there was ", err)
+			continue
+		}
+		if !reflect.DeepEqual(post.Tags, testCase.expectedTags) {
+			t.Error("The tags of ", testCase.json, " were expected to be ", testCase.expectedTags, " but they were ",
+				post.Tags)
+		}
+	}
+
+	var post BlogPost
+	if err := json.Unmarshal([]byte(`{"tags": 1}`), &post); err == nil {
+		t.Error("An error was expected for a number, but there wasn't.")
+	}
+}
+
+// TestKeyIsInternal tests that the key of a blog post is neither written to nor read from JSON.
+func TestKeyIsInternal(t *testing.T) {
+	postBytes, _ := json.Marshal(BlogPost{ID: "id", Key: "key"})
+	if strings.Contains(string(postBytes), `"key"`) {
+		t.Error("The JSON was expected not to have the key, but it was ", string(postBytes))
+	}
+
+	var post BlogPost
+	if err := json.Unmarshal([]byte(`{"id": "id", "key": "key"}`), &post); err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if post.Key != "" {
+		t.Errorf("The key was expected to be empty, but it was %s.", post.Key)
+	}
+}
+
+// TestStatusCanBecome tests that a status can only move forward in the workflow.
+func TestStatusCanBecome(t *testing.T) {
+	testCases := []struct {
+		from    Status
+		to      Status
+		allowed bool
+	}{
+		{StatusDraft, StatusDraft, true},
+		{StatusDraft, StatusPublished, true},
+		{StatusDraft, StatusArchived, false},
+		{StatusPublished, StatusArchived, true},
+		{StatusPublished, StatusDraft, false},
+		{StatusArchived, StatusArchived, true},
+		{StatusArchived, StatusPublished, false},
+	}
+
+	for _, testCase := range testCases {
+		if testCase.from.CanBecome(testCase.to) != testCase.allowed {
+			t.Error("The transition from ", testCase.from, " to ", testCase.to, " was expected to be allowed: ",
+				testCase.allowed)
+		}
+	}
+}
+
+// TestHasStatus tests that blog posts without a status are considered published.
+func TestHasStatus(t *testing.T) {
+	post := BlogPost{}
+	if !post.HasStatus(StatusPublished) || post.HasStatus(StatusDraft) {
+		t.Error("A blog post without a status was expected to be published.")
+	}
+
+	post.Status = StatusDraft
+	if !post.HasStatus(StatusDraft) || post.HasStatus(StatusPublished) {
+		t.Error("A draft blog post was expected to have the draft status.")
+	}
+}
+
+// TestCompare tests that only the fields that changed between two revisions are reported.
+func TestCompare(t *testing.T) {
+	from := BlogPost{ID: "id", Title: "title", Description: "descr", Tags: Tags{"a"}, Body: "line1\nline2",
+		Template: "template", Category: "category", Revision: 1, UpdateTimestamp: 100}
+	to := from
+	to.Title = "new title"
+	to.Tags = Tags{"a", "b"}
+	to.Body = "line1\nnew line2"
+	to.Status = StatusPublished
+	to.Revision = 2
+	to.UpdateTimestamp = 200
+
+	result := Compare(from, to)
+
+	expectedChanges := []FieldChange{
+		{Field: "title", From: "title", To: "new title"},
+		{Field: "tags", From: Tags{"a"}, To: Tags{"a", "b"}},
+		{Field: "body", From: "line1\nline2", To: "line1\nnew line2"},
+	}
+	if !reflect.DeepEqual(result.Changes, expectedChanges) {
+		t.Error("The changes were expected to be ", expectedChanges, " but they were ", result.Changes)
+	}
+	if result.ID != "id" || result.From != 1 || result.To != 2 {
+		t.Error("The diff was expected to be between revisions 1 and 2 of id, but it was ", result)
+	}
+
+	expectedBodyDiff := "--- revision 1\n+++ revision 2\n@@ -1,2 +1,2 @@\n line1\n-line2\n+new line2\n"
+	if result.BodyDiff != expectedBodyDiff {
+		t.Errorf("The body diff was expected to be:\n%s\nbut it was:\n%s", expectedBodyDiff, result.BodyDiff)
+	}
+}
+
+// TestPatchApply tests that merge patches and JSON patches change the fields of a blog post.
+func TestPatchApply(t *testing.T) {
+	post := BlogPost{ID: "id", Title: "title", Tags: Tags{"a", "b"}, Body: "body", Revision: 2}
+	testCases := []struct {
+		patch    Patch
+		expected BlogPost
+	}{
+		{Patch{Type: MergePatch, Document: []byte(`{"title":"new title","tags":"c, D"}`)},
+			BlogPost{ID: "id", Title: "new title", Tags: Tags{"c", "d"}, Body: "body", Revision: 2}},
+		{Patch{Type: MergePatch, Document: []byte(`{"body":null}`)},
+			BlogPost{ID: "id", Title: "title", Tags: Tags{"a", "b"}, Revision: 2}},
+		{Patch{Type: JSONPatch, Document: []byte(`[{"op":"add","path":"/tags/-","value":"c"}]`)},
+			BlogPost{ID: "id", Title: "title", Tags: Tags{"a", "b", "c"}, Body: "body", Revision: 2}},
+	}
+
+	for _, testCase := range testCases {
+		result, err := testCase.patch.Apply(post)
+
+		if err != nil {
+			t.Fatal("No error was expected, but there was ", err)
+		}
+		if !reflect.DeepEqual(result, testCase.expected) {
+			t.Errorf("The blog post was expected to be %+v, but it was %+v.", testCase.expected, result)
+		}
+	}
+}
+
+// TestPatchApplyWithInvalidPatch tests that a patch that doesn't result in a blog post is rejected.
+func TestPatchApplyWithInvalidPatch(t *testing.T) {
+	patches := []Patch{
+		{Type: MergePatch, Document: []byte(`{"title":1}`)},
+		{Type: MergePatch, Document: []byte(`not json`)},
+		{Type: JSONPatch, Document: []byte(`[{"op":"remove","path":"/missing"}]`)},
+	}
+
+	for _, p := range patches {
+		if _, err := p.Apply(BlogPost{ID: "id"}); err == nil {
+			t.Errorf("An error was expected for %s, but there was none.", p.Document)
+		}
+	}
+}
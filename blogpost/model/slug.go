@@ -0,0 +1,72 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength is the maximum length of a generated slug, which leaves room for a collision suffix.
+const maxSlugLength = 200
+
+// slugPattern matches slugs: lowercase letters and digits, in words that are separated by single hyphens.
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// transliterations holds the Latin letters of the Greek letters and of the Latin letters with diacritics. Every other
+// letter or digit outside ASCII is dropped.
+var transliterations = map[rune]string{
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i", 'ή': "i", 'θ': "th",
+	'ι': "i", 'ί': "i", 'ϊ': "i", 'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'ό': "o",
+	'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t", 'υ': "y", 'ύ': "y", 'ϋ': "y", 'ΰ': "y", 'φ': "f", 'χ': "ch",
+	'ψ': "ps", 'ω': "o", 'ώ': "o",
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a", 'æ': "ae",
+	'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e", 'ğ': "g",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i", 'ł': "l", 'ľ': "l",
+	'ñ': "n", 'ń': "n", 'ň': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o",
+	'œ': "oe", 'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ý': "y", 'ÿ': "y",
+	'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// Slug returns the URL-safe slug of a title, e.g. "Καλημέρα, Κόσμε!" becomes "kalimera-kosme". Greek letters and
+// Latin letters with diacritics are transliterated and every other character separates words. The slug is empty if
+// the title has no letters or digits.
+func Slug(title string) string {
+	var builder strings.Builder
+	separate := false
+	for _, r := range strings.ToLower(title) {
+		latin, ok := transliterations[r]
+		switch {
+		case ok:
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			latin = string(r)
+		default:
+			separate = builder.Len() > 0
+			continue
+		}
+
+		if separate {
+			latin = "-" + latin
+			separate = false
+		}
+		if builder.Len()+len(latin) > maxSlugLength {
+			break
+		}
+		builder.WriteString(latin)
+	}
+
+	return builder.String()
+}
+
+// SlugWithSuffix returns a slug with a number, which tells apart the blog posts whose titles have the same slug, e.g.
+// "hello-world-2".
+func SlugWithSuffix(slug string, number int) string {
+	return fmt.Sprintf("%s-%d", slug, number)
+}
+
+// IsSlug checks if a value has the format of a slug.
+func IsSlug(value string) bool {
+	return slugPattern.MatchString(value)
+}
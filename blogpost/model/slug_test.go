@@ -0,0 +1,59 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestSlug tests that titles are transliterated and turned to slugs.
+func TestSlug(t *testing.T) {
+	testCases := map[string]string{
+		"Hello World":                     "hello-world",
+		"  Go 1.21: What's new?  ":        "go-1-21-what-s-new",
+		"Καλημέρα, Κόσμε!":                "kalimera-kosme",
+		"Ψυχή και Θάλασσα":                "psychi-kai-thalassa",
+		"Crème brûlée à la française":     "creme-brulee-a-la-francaise",
+		"Straße & Łódź":                   "strasse-lodz",
+		"日本語":                             "",
+		"!!!":                             "",
+		strings.Repeat("word ", 100):      strings.TrimSuffix(strings.Repeat("word-", 40), "-"),
+		"emoji 🎉 in between":              "emoji-in-between",
+		"Trailing separators ---":         "trailing-separators",
+		"UPPER-case_and_underscores":      "upper-case-and-underscores",
+		"Ελληνικά ΆΈΉΊΌΎΏ":                "ellinika-aeiioyo",
+		"ς at the end of a word: λόγος":   "s-at-the-end-of-a-word-logos",
+		"numbers 123 and letters abc 456": "numbers-123-and-letters-abc-456",
+	}
+
+	for title, expectedSlug := range testCases {
+		if slug := Slug(title); slug != expectedSlug {
+			t.Errorf("The slug of %s was expected to be %s, but it was %s.", title, expectedSlug, slug)
+		}
+		if slug := Slug(title); slug != "" && !IsSlug(slug) {
+			t.Errorf("The slug of %s was expected to have the format of a slug, but it was %s.", title, slug)
+		}
+	}
+}
+
+// TestIsSlug tests that only lowercase letters, digits and single hyphens between them are slugs.
+func TestIsSlug(t *testing.T) {
+	testCases := map[string]bool{
+		"hello-world":  true,
+		"post-2":       true,
+		"a":            true,
+		"":             false,
+		"Hello":        false,
+		"hello world":  false,
+		"hello--world": false,
+		"-hello":       false,
+		"hello_world":  false,
+		"καλημέρα":     false,
+		"hello-world-": false,
+	}
+
+	for value, expected := range testCases {
+		if IsSlug(value) != expected {
+			t.Errorf("IsSlug(%s) was expected to be %t.", value, expected)
+		}
+	}
+}
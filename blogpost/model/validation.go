@@ -0,0 +1,176 @@
+package model
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/rules"
+)
+
+// Rule is a custom validation rule for blog posts. It returns the errors of a blog post, or none if it's valid.
+type Rule func(post BlogPost) []globalModel.Error
+
+// ValidationPolicy represents the configurable rules that blog posts are validated against, on top of the fixed ones.
+// Rules that are not set don't restrict anything.
+type ValidationPolicy struct {
+	// IDPattern is the regular expression that the id of every new blog post must match.
+	IDPattern *regexp.Regexp
+	// ReservedIDs are the ids that new blog posts may not have, e.g. because they would clash with other routes.
+	ReservedIDs []string
+	// Templates are the allowed templates.
+	Templates []string
+	// MaxBodySize is the maximum size of the body in bytes.
+	MaxBodySize int
+	// Rules are custom rules that are checked after the other ones.
+	Rules []Rule
+}
+
+// NewValidationPolicy creates a validation policy from the environment variables POST_ID_PATTERN, which is a regular
+// expression, POST_RESERVED_IDS and POST_TEMPLATES, which are comma-separated lists, and POST_MAX_BODY_SIZE. Ids must
+// be slugs unless POST_ID_PATTERN is set, and the other variables don't restrict anything if they are empty or not
+// valid.
+func NewValidationPolicy() ValidationPolicy {
+	policy := ValidationPolicy{
+		IDPattern:   slugPattern,
+		ReservedIDs: splitList(os.Getenv("POST_RESERVED_IDS")),
+		Templates:   splitList(os.Getenv("POST_TEMPLATES")),
+	}
+
+	if pattern := os.Getenv("POST_ID_PATTERN"); pattern != "" {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			log.Println("The id pattern of blog posts is not valid, so ids must be slugs: ", err)
+		} else {
+			policy.IDPattern = re
+		}
+	}
+	if size, err := strconv.Atoi(os.Getenv("POST_MAX_BODY_SIZE")); err == nil && size > 0 {
+		policy.MaxBodySize = size
+	}
+
+	return policy
+}
+
+// ForUpdates returns the policy without the rules of ids, which only apply to new blog posts, so that blog posts whose
+// ids were given before the rules can still be changed.
+func (policy ValidationPolicy) ForUpdates() ValidationPolicy {
+	policy.IDPattern = nil
+	policy.ReservedIDs = nil
+
+	return policy
+}
+
+// IsReserved checks if new blog posts may not have an id because it's reserved.
+func (policy ValidationPolicy) IsReserved(id string) bool {
+	for _, reservedID := range policy.ReservedIDs {
+		if id == reservedID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Validate checks if a BlogPost instance is valid against the fixed rules and returns its errors, or an empty list if
+// it's valid.
+func (post BlogPost) Validate() []globalModel.Error {
+	return post.ValidateWith(ValidationPolicy{})
+}
+
+// ValidateWith checks if a BlogPost instance is valid against the fixed rules and the rules of a policy, and returns
+// its errors, or an empty list if it's valid.
+func (post BlogPost) ValidateWith(policy ValidationPolicy) []globalModel.Error {
+	idRules := []validation.Rule{
+		rules.Required("The id is required."),
+		rules.MaxLength(250, "The id may have up to 250 characters."),
+	}
+	if policy.IDPattern != nil {
+		idRules = append(idRules, rules.Match(policy.IDPattern, "The id doesn't have a valid format."))
+	}
+	if len(policy.ReservedIDs) > 0 {
+		idRules = append(idRules, rules.NotIn("The id is reserved.", toInterfaces(policy.ReservedIDs)...))
+	}
+
+	templateRules := []validation.Rule{
+		rules.Required("The template is required."),
+		rules.MaxLength(50, "The template may have up tp 50 characters."),
+	}
+	if len(policy.Templates) > 0 {
+		templateRules = append(templateRules, rules.In(
+			fmt.Sprintf("The template must be one of %s.", strings.Join(policy.Templates, ", ")),
+			toInterfaces(policy.Templates)...))
+	}
+
+	bodyRules := []validation.Rule{rules.Required("The body is required.")}
+	if policy.MaxBodySize > 0 {
+		bodyRules = append(bodyRules, rules.MaxBytes(policy.MaxBodySize,
+			fmt.Sprintf("The body may have up to %d bytes.", policy.MaxBodySize)))
+	}
+
+	errs := rules.Errors(validation.ValidateStruct(
+		&post,
+		validation.Field(&post.ID, idRules...),
+		validation.Field(
+			&post.Title,
+			rules.Required("The title is required."),
+			rules.MaxLength(250, "The title may have up to 250 characters.")),
+		validation.Field(
+			&post.Description,
+			rules.Required("The description is required."),
+			rules.MaxLength(250, "The description may have up tp 250 characters.")),
+		validation.Field(
+			&post.Tags,
+			rules.Required("The tags are required."),
+			rules.MaxLength(10, "There may be up to 10 tags."),
+			validation.Each(rules.MaxLength(50, "Each tag may have up to 50 characters."))),
+		validation.Field(&post.Body, bodyRules...),
+		validation.Field(&post.Template, templateRules...),
+		validation.Field(
+			&post.Category,
+			rules.Required("The category is required."),
+			rules.MaxLength(250, "The category may have up tp 250 characters.")),
+		validation.Field(
+			&post.Revision,
+			rules.Required("The revision is required.")),
+		validation.Field(
+			&post.ScheduledAt,
+			rules.Min(0, "The scheduled time may not be negative.")),
+		validation.Field(
+			&post.Status,
+			rules.In("The status must be one of draft, published or archived.", StatusDraft, StatusPublished,
+				StatusArchived))))
+
+	for _, rule := range policy.Rules {
+		errs = append(errs, rule(post)...)
+	}
+
+	return errs
+}
+
+// splitList splits a comma-separated list and drops the empty items.
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
+
+// toInterfaces converts a list of strings to a list of values for the validation rules.
+func toInterfaces(values []string) []interface{} {
+	result := make([]interface{}, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+
+	return result
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// TestValidateWithPolicy tests that the rules of a validation policy are checked on top of the fixed ones.
+func TestValidateWithPolicy(t *testing.T) {
+	policy := ValidationPolicy{
+		IDPattern:   regexp.MustCompile(`^[a-z0-9-]+$`),
+		ReservedIDs: []string{"drafts", "trash"},
+		Templates:   []string{"post", "page"},
+		MaxBodySize: 10,
+		Rules: []Rule{func(post BlogPost) []globalModel.Error {
+			if post.Category == "hidden" {
+				return []globalModel.Error{globalModel.FieldError(globalModel.CodeNotAllowed, "category", "hidden")}
+			}
+			return nil
+		}},
+	}
+	valid := BlogPost{ID: "my-post", Title: "title", Description: "description", Tags: Tags{"tag"}, Body: "body",
+		Template: "post", Category: "category", Revision: 1}
+	testCases := []struct {
+		change         func(post *BlogPost)
+		expectedErrors []globalModel.Error
+	}{
+		{func(post *BlogPost) {}, []globalModel.Error{}},
+		{func(post *BlogPost) { post.ID = "My Post" }, []globalModel.Error{{Code: globalModel.CodeInvalidFormat,
+			Field: "id", Message: "The id doesn't have a valid format."}}},
+		{func(post *BlogPost) { post.ID = "trash" }, []globalModel.Error{{Code: globalModel.CodeReserved,
+			Field: "id", Message: "The id is reserved."}}},
+		{func(post *BlogPost) { post.Template = "other" }, []globalModel.Error{{Code: globalModel.CodeNotAllowed,
+			Field: "template", Message: "The template must be one of post, page."}}},
+		{func(post *BlogPost) { post.Body = "ελληνικά" }, []globalModel.Error{{Code: globalModel.CodeTooLong,
+			Field: "body", Message: "The body may have up to 10 bytes."}}},
+		{func(post *BlogPost) { post.Category = "hidden" }, []globalModel.Error{{Code: globalModel.CodeNotAllowed,
+			Field: "category", Message: "hidden"}}},
+	}
+
+	for _, testCase := range testCases {
+		post := valid
+		testCase.change(&post)
+
+		errs := post.ValidateWith(policy)
+
+		if !reflect.DeepEqual(errs, testCase.expectedErrors) {
+			t.Errorf("The errors were expected to be %v, but they were %v.", testCase.expectedErrors, errs)
+		}
+	}
+}
+
+// TestNewValidationPolicy tests that the validation policy is read from the environment, that ids must be slugs by
+// default and that invalid values are ignored.
+func TestNewValidationPolicy(t *testing.T) {
+	os.Setenv("POST_ID_PATTERN", "^[a-z]+$")
+	os.Setenv("POST_RESERVED_IDS", "drafts, trash,")
+	os.Setenv("POST_MAX_BODY_SIZE", "invalid")
+	defer os.Unsetenv("POST_ID_PATTERN")
+	defer os.Unsetenv("POST_RESERVED_IDS")
+	defer os.Unsetenv("POST_MAX_BODY_SIZE")
+
+	policy := NewValidationPolicy()
+
+	if policy.IDPattern == nil || policy.IDPattern.String() != "^[a-z]+$" {
+		t.Error("The id pattern was expected to be ^[a-z]+$, but it was ", policy.IDPattern)
+	}
+	if !reflect.DeepEqual(policy.ReservedIDs, []string{"drafts", "trash"}) || len(policy.Templates) > 0 {
+		t.Errorf("The lists were not read correctly: %v and %v.", policy.ReservedIDs, policy.Templates)
+	}
+	if policy.MaxBodySize != 0 {
+		t.Errorf("The maximum body size was expected to be 0, but it was %d.", policy.MaxBodySize)
+	}
+
+	os.Setenv("POST_ID_PATTERN", "[")
+	if policy = NewValidationPolicy(); policy.IDPattern != slugPattern {
+		t.Error("An invalid id pattern was expected to be replaced by slugs, but it was ", policy.IDPattern)
+	}
+	os.Unsetenv("POST_ID_PATTERN")
+	if policy = NewValidationPolicy(); policy.IDPattern != slugPattern {
+		t.Error("The default id pattern was expected to be slugs, but it was ", policy.IDPattern)
+	}
+}
+
+// TestForUpdates tests that the rules of ids don't apply to the blog posts that already exist.
+func TestForUpdates(t *testing.T) {
+	policy := ValidationPolicy{IDPattern: slugPattern, ReservedIDs: []string{"trash"}, Templates: []string{"post"}}
+	post := BlogPost{ID: "Old_ID", Title: "title", Description: "description", Tags: Tags{"tag"}, Body: "body",
+		Template: "post", Category: "category", Revision: 1}
+
+	if errs := post.ValidateWith(policy); len(errs) != 1 || errs[0].Field != "id" {
+		t.Error("The id was expected to be rejected for new blog posts, but the errors were ", errs)
+	}
+	if errs := post.ValidateWith(policy.ForUpdates()); len(errs) > 0 {
+		t.Error("The id was expected to be accepted for updates, but the errors were ", errs)
+	}
+	if policy.ForUpdates().Templates[0] != "post" {
+		t.Error("The other rules were expected to be kept for updates.")
+	}
+}
@@ -0,0 +1,65 @@
+package dynamodb
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// Backfill sets the entityType attribute of the blog posts that were created before it existed, so that the listings
+// of the global secondary indexes include them. It returns the number of blog posts that it changed. Running it again
+// is harmless, since it skips the blog posts that already have the attribute.
+func (repo *Repo) Backfill() (int, error) {
+	repo.createClient()
+
+	scanInput := &dynamodb.ScanInput{
+		TableName:            aws.String(repo.tableName),
+		FilterExpression:     aws.String("attribute_not_exists(entityType)"),
+		ProjectionExpression: aws.String("id"),
+	}
+
+	count := 0
+	var updateErr error
+	err := repo.client.ScanPages(scanInput, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			updated, err := repo.setEntityType(item["id"])
+			if err != nil {
+				updateErr = err
+				return false
+			}
+			if updated {
+				count++
+			}
+		}
+
+		return true
+	})
+	if err != nil {
+		return count, translateError(err, nil)
+	}
+
+	return count, updateErr
+}
+
+// setEntityType sets the entityType attribute of a blog post, unless it has been deleted or given one in the meantime.
+// It reports whether the blog post was changed.
+func (repo *Repo) setEntityType(id *dynamodb.AttributeValue) (bool, error) {
+	_, err := repo.client.UpdateItem(&dynamodb.UpdateItemInput{
+		TableName:           aws.String(repo.tableName),
+		Key:                 map[string]*dynamodb.AttributeValue{"id": id},
+		UpdateExpression:    aws.String("SET entityType = :entityType"),
+		ConditionExpression: aws.String("attribute_exists(id) AND attribute_not_exists(entityType)"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":entityType": {
+				S: aws.String(postEntityType),
+			},
+		},
+	})
+	if code, _, ok := errorCode(err); ok && code == dynamodb.ErrCodeConditionalCheckFailedException {
+		return false, nil
+	}
+	if err != nil {
+		return false, translateError(err, nil)
+	}
+
+	return true, nil
+}
@@ -1,20 +1,53 @@
 package dynamodb
 
 import (
+	"encoding/json"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
+	"github.com/printezisn/serverless-blog-back/global/cursor"
+)
+
+const (
+	// postEntityType is stored as the entityType attribute of every blog post item. Since all blog posts share the
+	// same value, the creation timestamp index can list all of them in order.
+	postEntityType = "post"
+	// creationTimestampIndex is the global secondary index that sorts blog posts by their creation timestamp.
+	creationTimestampIndex = "entityType-creationTimestamp-index"
+	// categoryIndex is the global secondary index that sorts the blog posts of each category by their creation
+	// timestamp.
+	categoryIndex = "category-creationTimestamp-index"
+	// authorIndex is the global secondary index that sorts the blog posts of each author by their creation timestamp.
+	authorIndex = "authorId-creationTimestamp-index"
+	// scheduleIndex is the global secondary index that sorts blog posts by the time they are scheduled to be
+	// published.
+	scheduleIndex = "entityType-scheduledAt-index"
+	// deletionIndex is the global secondary index that sorts blog posts by the time they were moved to the trash.
+	deletionIndex = "entityType-deletedTimestamp-index"
+	// batchWriteSize is the maximum number of items that a single BatchWriteItem request accepts.
+	batchWriteSize = 25
+	// tagIndex is the local secondary index of the tags table that sorts the blog posts of each tag by their
+	// creation timestamp.
+	tagIndex = "tag-creationTimestamp-index"
 )
 
 // Repo represents a repository for blog posts that uses DynamoDB.
 type Repo struct {
-	tableName string
-	client    *dynamodb.DynamoDB
+	tableName          string
+	tagsTableName      string
+	tagCountsTableName string
+	revisionsTableName string
+	client             *dynamodb.DynamoDB
+	cursors            cursor.Codec
 }
 
 // New returns a new repository instance for blog posts that uses DynamoDB.
@@ -24,40 +57,100 @@ func New() Repo {
 		tableName = "posts"
 	}
 
-	return Repo{tableName: tableName, client: nil}
+	tagsTableName, ok := os.LookupEnv("DYNAMODB_TAGS_TABLE_NAME")
+	if !ok {
+		tagsTableName = "post_tags"
+	}
+
+	tagCountsTableName, ok := os.LookupEnv("DYNAMODB_TAG_COUNTS_TABLE_NAME")
+	if !ok {
+		tagCountsTableName = "post_tag_counts"
+	}
+
+	revisionsTableName, ok := os.LookupEnv("DYNAMODB_REVISIONS_TABLE_NAME")
+	if !ok {
+		revisionsTableName = "post_revisions"
+	}
+
+	return Repo{tableName: tableName, tagsTableName: tagsTableName, tagCountsTableName: tagCountsTableName,
+		revisionsTableName: revisionsTableName, client: nil, cursors: cursor.New()}
 }
 
-// createClient creates a new DynamoDB client.
+// createClient creates a new DynamoDB client. If the DYNAMODB_ENDPOINT environment variable is set, the client
+// connects to that endpoint instead (e.g. DynamoDB Local).
 func (repo *Repo) createClient() {
 	if repo.client == nil {
 		session := session.Must(session.NewSessionWithOptions(session.Options{
 			SharedConfigState: session.SharedConfigEnable,
 		}))
-		repo.client = dynamodb.New(session)
+
+		config := aws.NewConfig()
+		if endpoint, ok := os.LookupEnv("DYNAMODB_ENDPOINT"); ok {
+			config = config.WithEndpoint(endpoint)
+		}
+
+		repo.client = dynamodb.New(session, config)
 	}
 }
 
-// Create creates a new blog post in the database.
+// Create creates a new blog post in the database, together with the index items and the counters of its tags.
 func (repo *Repo) Create(post model.BlogPost) (model.BlogPost, error) {
 	repo.createClient()
 
-	item, _ := dynamodbattribute.MarshalMap(post)
-	input := &dynamodb.PutItemInput{
-		Item:                item,
-		TableName:           aws.String(repo.tableName),
-		ConditionExpression: aws.String("attribute_not_exists(id)"),
+	item, err := dynamodbattribute.MarshalMap(post)
+	if err != nil {
+		return post, err
+	}
+	item["entityType"] = &dynamodb.AttributeValue{S: aws.String(postEntityType)}
+
+	transactItems := []*dynamodb.TransactWriteItem{
+		{
+			Put: &dynamodb.Put{
+				Item:                item,
+				TableName:           aws.String(repo.tableName),
+				ConditionExpression: aws.String("attribute_not_exists(id)"),
+			},
+		},
 	}
+	transactItems = append(transactItems, repo.putTagItems(post, post.CreationTimestamp)...)
+	transactItems = append(transactItems, repo.updateTagCounts(tagCountDeltas(nil, "", post.Tags,
+		post.CurrentStatus()))...)
 
-	_, err := repo.client.PutItem(input)
+	_, err = repo.client.TransactWriteItems(&dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
 
-	return post, err
+	return post, translateError(err, generic.ErrAlreadyExists)
 }
 
-// Update updates an existing blog post in the database.
+// Update updates an existing blog post in the database, together with the index items and the counters of its tags.
+// The previous revision of the blog post is kept as a snapshot.
 func (repo *Repo) Update(revision int64, post model.BlogPost) (model.BlogPost, error) {
 	repo.createClient()
 
-	input := &dynamodb.UpdateItemInput{
+	existingItem, err := repo.getItem(post.ID)
+	if err != nil {
+		return model.BlogPost{}, err
+	}
+	if existingItem == nil {
+		return model.BlogPost{}, generic.ErrNotFound
+	}
+	existingPost, err := unmarshalPost(existingItem)
+	if err != nil {
+		return model.BlogPost{}, err
+	}
+	if existingPost.Revision != revision {
+		return model.BlogPost{}, generic.ErrRevisionMismatch
+	}
+
+	tags, err := dynamodbattribute.Marshal(post.Tags)
+	if err != nil {
+		return model.BlogPost{}, err
+	}
+	status, err := dynamodbattribute.Marshal(post.Status)
+	if err != nil {
+		return model.BlogPost{}, err
+	}
+
+	update := &dynamodb.Update{
 		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
 			":title": {
 				S: aws.String(post.Title),
@@ -65,9 +158,7 @@ func (repo *Repo) Update(revision int64, post model.BlogPost) (model.BlogPost, e
 			":description": {
 				S: aws.String(post.Description),
 			},
-			":tags": {
-				S: aws.String(post.Tags),
-			},
+			":tags": tags,
 			":body": {
 				S: aws.String(post.Body),
 			},
@@ -86,6 +177,22 @@ func (repo *Repo) Update(revision int64, post model.BlogPost) (model.BlogPost, e
 			":newRevision": {
 				N: aws.String(strconv.FormatInt(post.Revision, 10)),
 			},
+			":entityType": {
+				S: aws.String(postEntityType),
+			},
+			":status": status,
+			":publishedTimestamp": {
+				N: aws.String(strconv.FormatInt(post.PublishedTimestamp, 10)),
+			},
+			":scheduledAt": {
+				N: aws.String(strconv.FormatInt(post.ScheduledAt, 10)),
+			},
+			":deletedTimestamp": {
+				N: aws.String(strconv.FormatInt(post.DeletedTimestamp, 10)),
+			},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#status": aws.String("status"),
 		},
 		TableName: aws.String(repo.tableName),
 		Key: map[string]*dynamodb.AttributeValue{
@@ -93,26 +200,65 @@ func (repo *Repo) Update(revision int64, post model.BlogPost) (model.BlogPost, e
 				S: aws.String(post.ID),
 			},
 		},
-		ReturnValues:        aws.String("ALL_NEW"),
 		ConditionExpression: aws.String("revision = :oldRevision"),
 		UpdateExpression: aws.String("set title = :title, description = :description, tags = :tags, " +
 			"body = :body, template = :template, category = :category, updateTimestamp = :updateTimestamp, " +
-			"revision = :newRevision"),
+			"revision = :newRevision, entityType = :entityType, #status = :status, " +
+			"publishedTimestamp = :publishedTimestamp, scheduledAt = :scheduledAt, " +
+			"deletedTimestamp = :deletedTimestamp"),
 	}
 
-	response, err := repo.client.UpdateItem(input)
+	// The index items of all tags are put again, so that blog posts from before the tag index get them too.
+	transactItems := []*dynamodb.TransactWriteItem{{Update: update}}
+	transactItems = append(transactItems, repo.putTagItems(post, existingPost.CreationTimestamp)...)
+	transactItems = append(transactItems, repo.deleteTagItems(post.ID, removedTags(existingPost.Tags, post.Tags))...)
+	transactItems = append(transactItems, repo.updateTagCounts(tagCountDeltas(countedTags(existingItem, existingPost),
+		existingPost.CurrentStatus(), post.Tags, post.CurrentStatus()))...)
+	revisionItem, err := repo.putRevisionItem(existingPost)
 	if err != nil {
 		return model.BlogPost{}, err
 	}
+	transactItems = append(transactItems, revisionItem)
+
+	_, err = repo.client.TransactWriteItems(&dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
+	if isConditionalCheckFailure(err) {
+		_, found, getErr := repo.Get(post.ID)
+		if getErr != nil {
+			return model.BlogPost{}, getErr
+		}
+		if !found {
+			return model.BlogPost{}, translateError(err, generic.ErrNotFound)
+		}
+
+		return model.BlogPost{}, translateError(err, generic.ErrRevisionMismatch)
+	}
+	if err != nil {
+		return model.BlogPost{}, translateError(err, nil)
+	}
 
-	var updatedPost model.BlogPost
-	err = dynamodbattribute.ConvertFromMap(response.Attributes, &updatedPost)
+	updatedPost := post
+	updatedPost.CreationTimestamp = existingPost.CreationTimestamp
 
-	return updatedPost, err
+	return updatedPost, nil
 }
 
 // Get searches and returns a blog post based on its id.
 func (repo *Repo) Get(id string) (model.BlogPost, bool, error) {
+	item, err := repo.getItem(id)
+	if err != nil || item == nil {
+		return model.BlogPost{}, false, err
+	}
+
+	post, err := unmarshalPost(item)
+	if err != nil {
+		return model.BlogPost{}, false, err
+	}
+
+	return post, true, nil
+}
+
+// getItem returns the database item of a blog post, or nil if it doesn't exist.
+func (repo *Repo) getItem(id string) (map[string]*dynamodb.AttributeValue, error) {
 	repo.createClient()
 
 	queryInput := &dynamodb.QueryInput{
@@ -131,88 +277,521 @@ func (repo *Repo) Get(id string) (model.BlogPost, bool, error) {
 
 	response, err := repo.client.Query(queryInput)
 	if err != nil {
-		return model.BlogPost{}, false, err
+		return nil, translateError(err, nil)
 	}
-
-	var posts []model.BlogPost
-	if err = dynamodbattribute.UnmarshalListOfMaps(response.Items, &posts); err != nil {
-		return model.BlogPost{}, false, err
+	if len(response.Items) == 0 {
+		return nil, nil
 	}
 
-	if len(posts) == 0 {
-		return model.BlogPost{}, false, err
+	return response.Items[0], nil
+}
+
+// GetAll loads the first page of blog posts with a status from the database, sorted by their creation timestamp.
+func (repo *Repo) GetAll(status model.Status, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.query(repo.creationTimestampQuery(status, order), "", nil, pageSize)
+}
+
+// GetMore loads the page of blog posts with a status that starts where the page of the cursor ended, sorted by their
+// creation timestamp.
+func (repo *Repo) GetMore(status model.Status, cursor string, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	input := repo.creationTimestampQuery(status, order)
+	startKey, err := repo.decodeCursor(*input.IndexName, "", cursor)
+	if err != nil {
+		return []model.BlogPost{}, "", err
 	}
 
-	return posts[0], true, nil
+	return repo.query(input, "", startKey, pageSize)
 }
 
-// GetAll loads all blog posts from the database.
-func (repo *Repo) GetAll(pageSize int64) ([]model.BlogPost, error) {
-	repo.createClient()
+// creationTimestampQuery returns the input of a query that lists all blog posts with a status by their creation
+// timestamp.
+func (repo *Repo) creationTimestampQuery(status model.Status, order generic.SortOrder) *dynamodb.QueryInput {
+	return withStatusFilter(&dynamodb.QueryInput{
+		TableName:              aws.String(repo.tableName),
+		IndexName:              aws.String(creationTimestampIndex),
+		KeyConditionExpression: aws.String("entityType = :entityType"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":entityType": {
+				S: aws.String(postEntityType),
+			},
+		},
+		ScanIndexForward: aws.Bool(order == generic.OldestFirst),
+	}, status)
+}
 
-	scanInput := &dynamodb.ScanInput{
-		TableName: aws.String(repo.tableName),
-		Limit:     aws.Int64(pageSize),
-	}
+// GetAllByCategory loads the first page of blog posts of a category with a status from the database, sorted by their
+// creation timestamp.
+func (repo *Repo) GetAllByCategory(category string, status model.Status, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.query(repo.categoryQuery(category, status, order), category, nil, pageSize)
+}
 
-	response, err := repo.client.Scan(scanInput)
+// GetMoreByCategory loads the page of blog posts of a category with a status that starts where the page of the
+// cursor ended, sorted by their creation timestamp.
+func (repo *Repo) GetMoreByCategory(category string, status model.Status, cursor string, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	input := repo.categoryQuery(category, status, order)
+	startKey, err := repo.decodeCursor(*input.IndexName, category, cursor)
 	if err != nil {
-		return []model.BlogPost{}, err
+		return []model.BlogPost{}, "", err
 	}
 
-	var posts []model.BlogPost
-	err = dynamodbattribute.UnmarshalListOfMaps(response.Items, &posts)
-	if posts == nil {
-		posts = []model.BlogPost{}
+	return repo.query(input, category, startKey, pageSize)
+}
+
+// categoryQuery returns the input of a query that lists the blog posts of a category with a status by their creation
+// timestamp.
+func (repo *Repo) categoryQuery(category string, status model.Status, order generic.SortOrder) *dynamodb.QueryInput {
+	return withStatusFilter(&dynamodb.QueryInput{
+		TableName:              aws.String(repo.tableName),
+		IndexName:              aws.String(categoryIndex),
+		KeyConditionExpression: aws.String("category = :category"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":category": {
+				S: aws.String(category),
+			},
+		},
+		ScanIndexForward: aws.Bool(order == generic.OldestFirst),
+	}, status)
+}
+
+// GetAllByAuthor loads the first page of blog posts of an author with a status from the database, sorted by their
+// creation timestamp.
+func (repo *Repo) GetAllByAuthor(authorID string, status model.Status, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.query(repo.authorQuery(authorID, status, order), authorID, nil, pageSize)
+}
+
+// GetMoreByAuthor loads the page of blog posts of an author with a status that starts where the page of the cursor
+// ended, sorted by their creation timestamp.
+func (repo *Repo) GetMoreByAuthor(authorID string, status model.Status, cursor string, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	input := repo.authorQuery(authorID, status, order)
+	startKey, err := repo.decodeCursor(*input.IndexName, authorID, cursor)
+	if err != nil {
+		return []model.BlogPost{}, "", err
 	}
 
+	return repo.query(input, authorID, startKey, pageSize)
+}
+
+// authorQuery returns the input of a query that lists the blog posts of an author with a status by their creation
+// timestamp.
+func (repo *Repo) authorQuery(authorID string, status model.Status, order generic.SortOrder) *dynamodb.QueryInput {
+	return withStatusFilter(&dynamodb.QueryInput{
+		TableName:              aws.String(repo.tableName),
+		IndexName:              aws.String(authorIndex),
+		KeyConditionExpression: aws.String("authorId = :authorId"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":authorId": {
+				S: aws.String(authorID),
+			},
+		},
+		ScanIndexForward: aws.Bool(order == generic.OldestFirst),
+	}, status)
+}
+
+// GetDue loads up to pageSize drafts from the database that are scheduled to be published at or before the
+// timestamp, earliest first.
+func (repo *Repo) GetDue(timestamp int64, pageSize int64) ([]model.BlogPost, error) {
+	posts, _, err := repo.query(repo.timestampQuery(scheduleIndex, "scheduledAt", timestamp, model.StatusDraft), "",
+		nil, pageSize)
+
 	return posts, err
 }
 
-// GetMore loads more blog posts from the database.
-func (repo *Repo) GetMore(lastID string, pageSize int64) ([]model.BlogPost, error) {
-	repo.createClient()
+// GetDeleted loads up to pageSize blog posts from the database that were moved to the trash at or before the
+// timestamp, earliest first.
+func (repo *Repo) GetDeleted(timestamp int64, pageSize int64) ([]model.BlogPost, error) {
+	posts, _, err := repo.query(repo.timestampQuery(deletionIndex, "deletedTimestamp", timestamp, model.StatusDeleted),
+		"", nil, pageSize)
 
-	scanInput := &dynamodb.ScanInput{
-		ExclusiveStartKey: map[string]*dynamodb.AttributeValue{
-			"id": {
-				S: aws.String(lastID),
+	return posts, err
+}
+
+// timestampQuery returns the input of a query that lists the blog posts with a status whose timestamp attribute is
+// set and is at or before the timestamp, earliest first. The index must sort blog posts by that attribute.
+func (repo *Repo) timestampQuery(index string, attribute string, timestamp int64,
+	status model.Status) *dynamodb.QueryInput {
+	return withStatusFilter(&dynamodb.QueryInput{
+		TableName:              aws.String(repo.tableName),
+		IndexName:              aws.String(index),
+		KeyConditionExpression: aws.String("entityType = :entityType AND " + attribute + " BETWEEN :first AND :timestamp"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":entityType": {
+				S: aws.String(postEntityType),
+			},
+			":first": {
+				N: aws.String("1"),
+			},
+			":timestamp": {
+				N: aws.String(strconv.FormatInt(timestamp, 10)),
 			},
 		},
-		TableName: aws.String(repo.tableName),
-		Limit:     aws.Int64(pageSize),
+		ScanIndexForward: aws.Bool(true),
+	}, status)
+}
+
+// withStatusFilter adds a filter to the query input, so that it only returns items with a status.
+func withStatusFilter(input *dynamodb.QueryInput, status model.Status) *dynamodb.QueryInput {
+	expression, names, values := statusFilter(status)
+	input.FilterExpression = aws.String(expression)
+	input.ExpressionAttributeNames = names
+	for name, value := range values {
+		input.ExpressionAttributeValues[name] = value
 	}
 
-	response, err := repo.client.Scan(scanInput)
-	if err != nil {
-		return []model.BlogPost{}, err
+	return input
+}
+
+// statusFilter returns a filter expression that matches the items with a status, together with the attribute names
+// and values that it uses. Items without a status (or with a null one) are considered published.
+func statusFilter(status model.Status) (string, map[string]*string, map[string]*dynamodb.AttributeValue) {
+	names := map[string]*string{"#status": aws.String("status")}
+	values := map[string]*dynamodb.AttributeValue{":status": {S: aws.String(string(status))}}
+	if status != model.StatusPublished {
+		return "#status = :status", names, values
 	}
 
-	var posts []model.BlogPost
-	err = dynamodbattribute.UnmarshalListOfMaps(response.Items, &posts)
-	if posts == nil {
-		posts = []model.BlogPost{}
+	values[":stringType"] = &dynamodb.AttributeValue{S: aws.String(dynamodb.ScalarAttributeTypeS)}
+
+	return "(NOT attribute_type(#status, :stringType) OR #status = :status)", names, values
+}
+
+// query loads up to pageSize blog posts with the query input, starting after startKey. Since DynamoDB may return
+// fewer items than requested (e.g. when it reaches the 1 MB limit), it keeps querying until the page is full or
+// there are no more items. It returns the posts and the cursor of the last evaluated key, or an empty cursor if there
+// are no more posts. The cursor is bound to the partition that was queried, e.g. the category.
+func (repo *Repo) query(input *dynamodb.QueryInput, partition string, startKey map[string]*dynamodb.AttributeValue,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.queryWith(input, partition, startKey, pageSize, unmarshalPosts)
+}
+
+// queryWith works like query, but uses load to turn the queried items into blog posts.
+func (repo *Repo) queryWith(input *dynamodb.QueryInput, partition string,
+	startKey map[string]*dynamodb.AttributeValue, pageSize int64,
+	load func([]map[string]*dynamodb.AttributeValue) ([]model.BlogPost, error)) ([]model.BlogPost, string, error) {
+	repo.createClient()
+
+	posts := []model.BlogPost{}
+	for {
+		input.ExclusiveStartKey = startKey
+		input.Limit = aws.Int64(pageSize - int64(len(posts)))
+
+		response, err := repo.client.Query(input)
+		if err != nil {
+			return []model.BlogPost{}, "", translateError(err, nil)
+		}
+
+		pagePosts, err := load(response.Items)
+		if err != nil {
+			return []model.BlogPost{}, "", err
+		}
+		posts = append(posts, pagePosts...)
+
+		startKey = response.LastEvaluatedKey
+		if len(startKey) == 0 || int64(len(posts)) >= pageSize {
+			break
+		}
 	}
 
-	return posts, err
+	// DynamoDB returns a last evaluated key for a full page even if it's the last one, so the cursor is only kept if
+	// there are more posts.
+	if len(startKey) > 0 {
+		more, err := repo.hasMore(input, startKey, pageSize, load)
+		if err != nil {
+			return []model.BlogPost{}, "", err
+		}
+		if !more {
+			startKey = nil
+		}
+	}
+
+	cursor, err := repo.encodeCursor(aws.StringValue(input.IndexName), partition, startKey)
+
+	return posts, cursor, err
+}
+
+// hasMore checks if the query input has blog posts after startKey. It reads up to pageSize items at a time, since
+// load may skip some of them.
+func (repo *Repo) hasMore(input *dynamodb.QueryInput, startKey map[string]*dynamodb.AttributeValue, pageSize int64,
+	load func([]map[string]*dynamodb.AttributeValue) ([]model.BlogPost, error)) (bool, error) {
+	for len(startKey) > 0 {
+		input.ExclusiveStartKey = startKey
+		input.Limit = aws.Int64(pageSize)
+
+		response, err := repo.client.Query(input)
+		if err != nil {
+			return false, translateError(err, nil)
+		}
+
+		posts, err := load(response.Items)
+		if err != nil {
+			return false, err
+		}
+		if len(posts) > 0 {
+			return true, nil
+		}
+
+		startKey = response.LastEvaluatedKey
+	}
+
+	return false, nil
 }
 
-// Delete deletes a blog post from the database.
-func (repo *Repo) Delete(id string) (bool, error) {
+// Delete deletes a blog post from the database, together with the index items of its tags and its revisions, if it
+// still has the revision. The counters of its tags are decreased.
+func (repo *Repo) Delete(revision int64, id string) (bool, error) {
 	repo.createClient()
 
 	deleteInput := &dynamodb.DeleteItemInput{
 		Key: map[string]*dynamodb.AttributeValue{
 			"id": {S: aws.String(id)},
 		},
+		ConditionExpression: aws.String("revision = :revision"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":revision": {N: aws.String(strconv.FormatInt(revision, 10))},
+		},
 		ReturnValues: aws.String("ALL_OLD"),
 		TableName:    aws.String(repo.tableName),
 	}
 
 	response, err := repo.client.DeleteItem(deleteInput)
+	if isConditionalCheckFailure(err) {
+		// The condition also fails when the blog post doesn't exist.
+		_, found, getErr := repo.Get(id)
+		if getErr != nil {
+			return false, getErr
+		}
+		if !found {
+			return false, nil
+		}
+
+		return false, translateError(err, generic.ErrRevisionMismatch)
+	}
+	if err != nil {
+		return false, translateError(err, nil)
+	}
+	if len(response.Attributes) == 0 {
+		return false, nil
+	}
+
+	deletedPost, err := unmarshalPost(response.Attributes)
 	if err != nil {
-		return false, err
+		return true, err
+	}
+
+	if err = repo.removeTags(deletedPost.ID, countedTags(response.Attributes, deletedPost),
+		deletedPost.CurrentStatus()); err != nil {
+		return true, err
+	}
+
+	return true, repo.removeRevisionItems(deletedPost.ID)
+}
+
+// batchDelete deletes items from a table by their keys, in batches.
+func (repo *Repo) batchDelete(tableName string, keys []map[string]*dynamodb.AttributeValue) error {
+	for start := 0; start < len(keys); start += batchWriteSize {
+		end := start + batchWriteSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		requests := make([]*dynamodb.WriteRequest, 0, end-start)
+		for _, key := range keys[start:end] {
+			requests = append(requests, &dynamodb.WriteRequest{
+				DeleteRequest: &dynamodb.DeleteRequest{Key: key},
+			})
+		}
+
+		requestItems := map[string][]*dynamodb.WriteRequest{tableName: requests}
+		for len(requestItems) > 0 {
+			response, err := repo.client.BatchWriteItem(&dynamodb.BatchWriteItemInput{RequestItems: requestItems})
+			if err != nil {
+				return translateError(err, nil)
+			}
+
+			requestItems = response.UnprocessedItems
+		}
+	}
+
+	return nil
+}
+
+// unmarshalPosts converts a list of database items to blog posts.
+func unmarshalPosts(items []map[string]*dynamodb.AttributeValue) ([]model.BlogPost, error) {
+	posts := make([]model.BlogPost, 0, len(items))
+	for _, item := range items {
+		post, err := unmarshalPost(item)
+		if err != nil {
+			return []model.BlogPost{}, err
+		}
+
+		posts = append(posts, post)
+	}
+
+	return posts, nil
+}
+
+// unmarshalPost converts a database item to a blog post. Blog posts from before the tag list keep their tags in a
+// single comma-separated string, which is split into the list, and blog posts from before the workflow have no status,
+// so they are considered published since their creation.
+func unmarshalPost(item map[string]*dynamodb.AttributeValue) (model.BlogPost, error) {
+	if tags, ok := item["tags"]; ok && tags.S != nil {
+		legacyTags, err := dynamodbattribute.Marshal(model.ParseTags(aws.StringValue(tags.S)))
+		if err != nil {
+			return model.BlogPost{}, err
+		}
+
+		converted := make(map[string]*dynamodb.AttributeValue, len(item))
+		for name, value := range item {
+			converted[name] = value
+		}
+		converted["tags"] = legacyTags
+		item = converted
+	}
+
+	var post model.BlogPost
+	if err := dynamodbattribute.UnmarshalMap(item, &post); err != nil {
+		return model.BlogPost{}, err
+	}
+
+	if post.Status == "" {
+		post.Status = model.StatusPublished
+		post.PublishedTimestamp = post.CreationTimestamp
+	}
+
+	return post, nil
+}
+
+// cursorPayload represents the contents of a cursor: the index and the partition that were queried, and the last
+// evaluated key.
+type cursorPayload struct {
+	Index     string                       `json:"index"`
+	Partition string                       `json:"partition,omitempty"`
+	Key       map[string]map[string]string `json:"key"`
+}
+
+// encodeCursor turns the last evaluated key of a DynamoDB operation on the partition of an index into a cursor. An
+// empty key results in an empty cursor.
+func (repo *Repo) encodeCursor(index string, partition string, key map[string]*dynamodb.AttributeValue) (string,
+	error) {
+	if len(key) == 0 {
+		return "", nil
+	}
+
+	values := map[string]map[string]string{}
+	for name, value := range key {
+		switch {
+		case value.S != nil:
+			values[name] = map[string]string{"S": *value.S}
+		case value.N != nil:
+			values[name] = map[string]string{"N": *value.N}
+		default:
+			return "", fmt.Errorf("the key attribute %s has an unsupported type", name)
+		}
+	}
+
+	payload, err := json.Marshal(cursorPayload{Index: index, Partition: partition, Key: values})
+	if err != nil {
+		return "", err
+	}
+
+	return repo.cursors.Encode(payload), nil
+}
+
+// decodeCursor turns a cursor back into the exclusive start key of a DynamoDB operation on the partition of an index.
+// Cursors that were created for another index or partition are rejected, since DynamoDB fails with a start key that
+// doesn't match the key condition.
+func (repo *Repo) decodeCursor(index string, partition string, cursor string) (map[string]*dynamodb.AttributeValue,
+	error) {
+	payload, err := repo.cursors.Decode(cursor)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", generic.ErrInvalidCursor, err)
+	}
+
+	var contents cursorPayload
+	if err = json.Unmarshal(payload, &contents); err != nil || contents.Index != index ||
+		contents.Partition != partition || len(contents.Key) == 0 {
+		return nil, generic.ErrInvalidCursor
+	}
+
+	key := map[string]*dynamodb.AttributeValue{}
+	for name, value := range contents.Key {
+		if s, ok := value["S"]; ok {
+			key[name] = &dynamodb.AttributeValue{S: aws.String(s)}
+		} else if n, ok := value["N"]; ok {
+			key[name] = &dynamodb.AttributeValue{N: aws.String(n)}
+		} else {
+			return nil, generic.ErrInvalidCursor
+		}
+	}
+
+	return key, nil
+}
+
+// isConditionalCheckFailure checks if an error was returned because a condition expression failed.
+func isConditionalCheckFailure(err error) bool {
+	code, _, ok := errorCode(err)
+
+	return ok && code == dynamodb.ErrCodeConditionalCheckFailedException
+}
+
+// translateError translates a DynamoDB error to the matching repository error, keeping the original error in the
+// message. A failed condition expression is translated to conditionalErr.
+func translateError(err error, conditionalErr error) error {
+	code, message, ok := errorCode(err)
+	if !ok {
+		return err
+	}
+
+	var repoErr error
+	switch code {
+	case dynamodb.ErrCodeConditionalCheckFailedException:
+		repoErr = conditionalErr
+	case dynamodb.ErrCodeProvisionedThroughputExceededException, dynamodb.ErrCodeRequestLimitExceeded,
+		"ThrottlingException":
+		repoErr = generic.ErrThrottled
+	case dynamodb.ErrCodeItemCollectionSizeLimitExceededException:
+		repoErr = generic.ErrTooLarge
+	case "ValidationException":
+		if strings.Contains(strings.ToLower(message), "size has exceeded") {
+			repoErr = generic.ErrTooLarge
+		}
+	}
+	if repoErr == nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: %v", repoErr, err)
+}
+
+// transactionReasonCodes maps the reasons that cancel a transaction to the codes of the matching errors.
+var transactionReasonCodes = map[string]string{
+	"ConditionalCheckFailed":          dynamodb.ErrCodeConditionalCheckFailedException,
+	"ThrottlingError":                 "ThrottlingException",
+	"ProvisionedThroughputExceeded":   dynamodb.ErrCodeProvisionedThroughputExceededException,
+	"ItemCollectionSizeLimitExceeded": dynamodb.ErrCodeItemCollectionSizeLimitExceededException,
+	"ValidationError":                 "ValidationException",
+}
+
+// errorCode returns the code and the message of a DynamoDB error. When a transaction is cancelled, they are taken
+// from the first reason that cancelled it.
+func errorCode(err error) (string, string, bool) {
+	if canceled, ok := err.(*dynamodb.TransactionCanceledException); ok {
+		for _, reason := range canceled.CancellationReasons {
+			if code, ok := transactionReasonCodes[aws.StringValue(reason.Code)]; ok {
+				return code, aws.StringValue(reason.Message), true
+			}
+		}
+	}
+
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return "", "", false
 	}
 
-	return len(response.Attributes) > 0, err
+	return awsErr.Code(), awsErr.Message(), true
 }
@@ -0,0 +1,370 @@
+package dynamodb
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/repotest"
+)
+
+// TestRepo runs the repository test suite against DynamoDB. It only runs when the DYNAMODB_ENDPOINT environment
+// variable points to a DynamoDB instance that can be used for testing (e.g. DynamoDB Local).
+func TestRepo(t *testing.T) {
+	if _, ok := os.LookupEnv("DYNAMODB_ENDPOINT"); !ok {
+		t.Skip("DYNAMODB_ENDPOINT is not set.")
+	}
+
+	os.Setenv("DYNAMODB_TABLE_NAME", "posts_test")
+	os.Setenv("DYNAMODB_TAGS_TABLE_NAME", "post_tags_test")
+	os.Setenv("DYNAMODB_TAG_COUNTS_TABLE_NAME", "post_tag_counts_test")
+	os.Setenv("DYNAMODB_REVISIONS_TABLE_NAME", "post_revisions_test")
+	repo := New()
+	repo.createClient()
+	createTestTables(t, &repo)
+
+	repotest.Run(t, func() generic.Repo {
+		clearTestTable(t, &repo, repo.tableName, "id")
+		clearTestTable(t, &repo, repo.tagsTableName, "tag", "postId")
+		clearTestTable(t, &repo, repo.tagCountsTableName, "status", "tag")
+		clearTestTable(t, &repo, repo.revisionsTableName, "id", "revision")
+		return &repo
+	})
+}
+
+// TestBackfill tests that the Backfill method adds the blog posts without the entityType attribute to the listings,
+// and only once. It only runs when the DYNAMODB_ENDPOINT environment variable is set, like TestRepo.
+func TestBackfill(t *testing.T) {
+	if _, ok := os.LookupEnv("DYNAMODB_ENDPOINT"); !ok {
+		t.Skip("DYNAMODB_ENDPOINT is not set.")
+	}
+
+	os.Setenv("DYNAMODB_TABLE_NAME", "posts_test")
+	os.Setenv("DYNAMODB_TAGS_TABLE_NAME", "post_tags_test")
+	os.Setenv("DYNAMODB_TAG_COUNTS_TABLE_NAME", "post_tag_counts_test")
+	os.Setenv("DYNAMODB_REVISIONS_TABLE_NAME", "post_revisions_test")
+	repo := New()
+	repo.createClient()
+	createTestTables(t, &repo)
+
+	_, err := repo.client.PutItem(&dynamodb.PutItemInput{
+		TableName: aws.String(repo.tableName),
+		Item: map[string]*dynamodb.AttributeValue{
+			"id":                {S: aws.String("legacy")},
+			"title":             {S: aws.String("title")},
+			"creationTimestamp": {N: aws.String("1")},
+		},
+	})
+	if err != nil {
+		t.Fatal("The legacy blog post could not be created: ", err)
+	}
+
+	for i, expectedCount := range []int{1, 0} {
+		count, err := repo.Backfill()
+		if err != nil {
+			t.Fatal("No error was expected, but there was ", err)
+		}
+		if count != expectedCount {
+			t.Errorf("The count of run %d was expected to be %d, but it was %d.", i+1, expectedCount, count)
+		}
+	}
+
+	posts, _, err := repo.GetAll(model.StatusPublished, generic.NewestFirst, 10)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if len(posts) != 1 || posts[0].ID != "legacy" {
+		t.Error("The legacy blog post was expected to be listed, but the posts were ", posts)
+	}
+}
+
+// TestTranslateError tests that DynamoDB errors are translated to the matching repository errors.
+func TestTranslateError(t *testing.T) {
+	unexpectedErr := errors.New("unexpected error")
+	testCases := []struct {
+		err            error
+		conditionalErr error
+		expectedErr    error
+	}{
+		{awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "error", nil), generic.ErrAlreadyExists,
+			generic.ErrAlreadyExists},
+		{awserr.New(dynamodb.ErrCodeConditionalCheckFailedException, "error", nil), generic.ErrRevisionMismatch,
+			generic.ErrRevisionMismatch},
+		{awserr.New(dynamodb.ErrCodeProvisionedThroughputExceededException, "error", nil), nil, generic.ErrThrottled},
+		{awserr.New(dynamodb.ErrCodeRequestLimitExceeded, "error", nil), nil, generic.ErrThrottled},
+		{awserr.New("ValidationException", "Item size has exceeded the maximum allowed size", nil), nil,
+			generic.ErrTooLarge},
+		{transactionCanceled("None", "ConditionalCheckFailed"), generic.ErrRevisionMismatch,
+			generic.ErrRevisionMismatch},
+		{transactionCanceled("ThrottlingError", "None"), nil, generic.ErrThrottled},
+		{unexpectedErr, nil, unexpectedErr},
+	}
+
+	for _, testCase := range testCases {
+		err := translateError(testCase.err, testCase.conditionalErr)
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Error("The error ", testCase.err, " was expected to be translated to ", testCase.expectedErr,
+				" but it was ", err)
+		}
+	}
+
+	if translateError(nil, generic.ErrAlreadyExists) != nil {
+		t.Error("A nil error was expected to be translated to nil.")
+	}
+}
+
+// TestCursorWithKey tests that a cursor turns back into the key it was created from, and only for its index and
+// partition.
+func TestCursorWithKey(t *testing.T) {
+	repo := New()
+	key := map[string]*dynamodb.AttributeValue{
+		"id":                {S: aws.String("id")},
+		"creationTimestamp": {N: aws.String("100")},
+	}
+
+	cursor, err := repo.encodeCursor(categoryIndex, "category", key)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+
+	decodedKey, err := repo.decodeCursor(categoryIndex, "category", cursor)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if *decodedKey["id"].S != "id" || *decodedKey["creationTimestamp"].N != "100" || len(decodedKey) != 2 {
+		t.Error("The key was expected to be ", key, " but it was ", decodedKey)
+	}
+
+	if _, err = repo.decodeCursor(categoryIndex, "category", cursor+"x"); !errors.Is(err, generic.ErrInvalidCursor) {
+		t.Error("The error was expected to be ErrInvalidCursor, but it was ", err)
+	}
+	if _, err = repo.decodeCursor("other-index", "category", cursor); !errors.Is(err, generic.ErrInvalidCursor) {
+		t.Error("The error was expected to be ErrInvalidCursor, but it was ", err)
+	}
+	if _, err = repo.decodeCursor(categoryIndex, "other", cursor); !errors.Is(err, generic.ErrInvalidCursor) {
+		t.Error("The error was expected to be ErrInvalidCursor, but it was ", err)
+	}
+}
+
+// TestTagCountDeltas tests that the tag counters only change for the tags that are added or removed, or whose blog
+// post changes its status.
+func TestTagCountDeltas(t *testing.T) {
+	testCases := []struct {
+		oldTags        model.Tags
+		oldStatus      model.Status
+		newTags        model.Tags
+		newStatus      model.Status
+		expectedDeltas map[tagCounter]int64
+	}{
+		{nil, "", model.Tags{"go"}, model.StatusDraft, map[tagCounter]int64{{model.StatusDraft, "go"}: 1}},
+		{model.Tags{"go"}, model.StatusDraft, nil, "", map[tagCounter]int64{{model.StatusDraft, "go"}: -1}},
+		{model.Tags{"go", "aws"}, model.StatusDraft, model.Tags{"go", "lambda"}, model.StatusDraft,
+			map[tagCounter]int64{{model.StatusDraft, "aws"}: -1, {model.StatusDraft, "lambda"}: 1}},
+		{model.Tags{"go"}, model.StatusDraft, model.Tags{"go"}, model.StatusPublished,
+			map[tagCounter]int64{{model.StatusDraft, "go"}: -1, {model.StatusPublished, "go"}: 1}},
+		{model.Tags{"go"}, model.StatusDraft, model.Tags{"go"}, model.StatusDraft, map[tagCounter]int64{}},
+	}
+
+	for _, testCase := range testCases {
+		deltas := tagCountDeltas(testCase.oldTags, testCase.oldStatus, testCase.newTags, testCase.newStatus)
+		if !reflect.DeepEqual(deltas, testCase.expectedDeltas) {
+			t.Error("The deltas were expected to be ", testCase.expectedDeltas, " but they were ", deltas)
+		}
+	}
+}
+
+// TestCountedTagsWithLegacyTags tests that the comma-separated tags of older blog posts are not counted, since they
+// have no index items.
+func TestCountedTagsWithLegacyTags(t *testing.T) {
+	post := model.BlogPost{ID: "id", Tags: model.Tags{"go"}}
+
+	legacyItem := map[string]*dynamodb.AttributeValue{"tags": {S: aws.String("go")}}
+	if tags := countedTags(legacyItem, post); len(tags) != 0 {
+		t.Error("The counted tags were expected to be empty, but they were ", tags)
+	}
+	item := map[string]*dynamodb.AttributeValue{"tags": {L: []*dynamodb.AttributeValue{{S: aws.String("go")}}}}
+	if tags := countedTags(item, post); !reflect.DeepEqual(tags, post.Tags) {
+		t.Error("The counted tags were expected to be ", post.Tags, " but they were ", tags)
+	}
+}
+
+// TestUnmarshalPostWithLegacyTags tests that the comma-separated tags of older blog posts are split into a list.
+func TestUnmarshalPostWithLegacyTags(t *testing.T) {
+	item := map[string]*dynamodb.AttributeValue{
+		"id":   {S: aws.String("id")},
+		"tags": {S: aws.String("Go, serverless,,go")},
+	}
+
+	post, err := unmarshalPost(item)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+
+	expectedTags := model.Tags{"go", "serverless"}
+	if !reflect.DeepEqual(post.Tags, expectedTags) {
+		t.Error("The tags were expected to be ", expectedTags, " but they were ", post.Tags)
+	}
+	if aws.StringValue(item["tags"].S) != "Go, serverless,,go" {
+		t.Error("The item was expected to stay untouched, but it was changed to ", item)
+	}
+}
+
+// transactionCanceled returns the error of a transaction that was cancelled for the given reasons.
+func transactionCanceled(codes ...string) error {
+	reasons := make([]*dynamodb.CancellationReason, 0, len(codes))
+	for _, code := range codes {
+		reasons = append(reasons, &dynamodb.CancellationReason{Code: aws.String(code)})
+	}
+
+	return &dynamodb.TransactionCanceledException{CancellationReasons: reasons}
+}
+
+// createTestTables (re)creates the tables used by the tests, so that they always have the latest schema.
+func createTestTables(t *testing.T, repo *Repo) {
+	createTestTable(t, repo, &dynamodb.CreateTableInput{
+		TableName: aws.String(repo.tableName),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("entityType"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("creationTimestamp"), AttributeType: aws.String("N")},
+			{AttributeName: aws.String("category"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("authorId"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("scheduledAt"), AttributeType: aws.String("N")},
+			{AttributeName: aws.String("deletedTimestamp"), AttributeType: aws.String("N")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+		},
+		GlobalSecondaryIndexes: []*dynamodb.GlobalSecondaryIndex{
+			testIndex(creationTimestampIndex, "entityType", "creationTimestamp"),
+			testIndex(categoryIndex, "category", "creationTimestamp"),
+			testIndex(authorIndex, "authorId", "creationTimestamp"),
+			testIndex(scheduleIndex, "entityType", "scheduledAt"),
+			testIndex(deletionIndex, "entityType", "deletedTimestamp"),
+		},
+		ProvisionedThroughput: testThroughput(),
+	})
+
+	createTestTable(t, repo, &dynamodb.CreateTableInput{
+		TableName: aws.String(repo.tagsTableName),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("tag"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("postId"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("creationTimestamp"), AttributeType: aws.String("N")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("tag"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("postId"), KeyType: aws.String("RANGE")},
+		},
+		LocalSecondaryIndexes: []*dynamodb.LocalSecondaryIndex{
+			{
+				IndexName: aws.String(tagIndex),
+				KeySchema: []*dynamodb.KeySchemaElement{
+					{AttributeName: aws.String("tag"), KeyType: aws.String("HASH")},
+					{AttributeName: aws.String("creationTimestamp"), KeyType: aws.String("RANGE")},
+				},
+				Projection: &dynamodb.Projection{ProjectionType: aws.String("KEYS_ONLY")},
+			},
+		},
+		ProvisionedThroughput: testThroughput(),
+	})
+
+	createTestTable(t, repo, &dynamodb.CreateTableInput{
+		TableName: aws.String(repo.tagCountsTableName),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("status"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("tag"), AttributeType: aws.String("S")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("status"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("tag"), KeyType: aws.String("RANGE")},
+		},
+		ProvisionedThroughput: testThroughput(),
+	})
+
+	createTestTable(t, repo, &dynamodb.CreateTableInput{
+		TableName: aws.String(repo.revisionsTableName),
+		AttributeDefinitions: []*dynamodb.AttributeDefinition{
+			{AttributeName: aws.String("id"), AttributeType: aws.String("S")},
+			{AttributeName: aws.String("revision"), AttributeType: aws.String("N")},
+		},
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String("id"), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String("revision"), KeyType: aws.String("RANGE")},
+		},
+		ProvisionedThroughput: testThroughput(),
+	})
+}
+
+// createTestTable deletes the table of the input, if it exists, and creates it again.
+func createTestTable(t *testing.T, repo *Repo, input *dynamodb.CreateTableInput) {
+	describeInput := &dynamodb.DescribeTableInput{TableName: input.TableName}
+	if _, err := repo.client.DescribeTable(describeInput); err == nil {
+		repo.client.DeleteTable(&dynamodb.DeleteTableInput{TableName: input.TableName})
+		if err = repo.client.WaitUntilTableNotExists(describeInput); err != nil {
+			t.Fatal("The old test table could not be deleted: ", err)
+		}
+	}
+
+	if _, err := repo.client.CreateTable(input); err != nil {
+		t.Fatal("The test table could not be created: ", err)
+	}
+	if err := repo.client.WaitUntilTableExists(describeInput); err != nil {
+		t.Fatal("The test table could not be created: ", err)
+	}
+}
+
+// testIndex returns the definition of a global secondary index for the blog posts test table.
+func testIndex(name, hashKey, rangeKey string) *dynamodb.GlobalSecondaryIndex {
+	return &dynamodb.GlobalSecondaryIndex{
+		IndexName: aws.String(name),
+		KeySchema: []*dynamodb.KeySchemaElement{
+			{AttributeName: aws.String(hashKey), KeyType: aws.String("HASH")},
+			{AttributeName: aws.String(rangeKey), KeyType: aws.String("RANGE")},
+		},
+		Projection:            &dynamodb.Projection{ProjectionType: aws.String("ALL")},
+		ProvisionedThroughput: testThroughput(),
+	}
+}
+
+// testThroughput returns the provisioned throughput of the test tables and their indexes.
+func testThroughput() *dynamodb.ProvisionedThroughput {
+	return &dynamodb.ProvisionedThroughput{
+		ReadCapacityUnits:  aws.Int64(5),
+		WriteCapacityUnits: aws.Int64(5),
+	}
+}
+
+// clearTestTable deletes every item from a table used by the tests. The key names are the attributes of the table's
+// primary key.
+func clearTestTable(t *testing.T, repo *Repo, tableName string, keyNames ...string) {
+	err := repo.client.ScanPages(
+		&dynamodb.ScanInput{TableName: aws.String(tableName)},
+		func(page *dynamodb.ScanOutput, lastPage bool) bool {
+			for _, item := range page.Items {
+				key := map[string]*dynamodb.AttributeValue{}
+				for _, keyName := range keyNames {
+					key[keyName] = item[keyName]
+				}
+
+				_, err := repo.client.DeleteItem(&dynamodb.DeleteItemInput{
+					TableName: aws.String(tableName),
+					Key:       key,
+				})
+				if err != nil {
+					t.Fatal("The test table could not be cleared: ", err)
+				}
+			}
+
+			return true
+		})
+	if err != nil {
+		t.Fatal("The test table could not be cleared: ", err)
+	}
+}
@@ -0,0 +1,110 @@
+package dynamodb
+
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
+	"github.com/printezisn/serverless-blog-back/blogpost/model"
+)
+
+// GetRevisions loads the previous revisions of a blog post from the database, newest first.
+func (repo *Repo) GetRevisions(id string) ([]model.BlogPost, error) {
+	repo.createClient()
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:              aws.String(repo.revisionsTableName),
+		KeyConditionExpression: aws.String("id = :id"),
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":id": {
+				S: aws.String(id),
+			},
+		},
+		ScanIndexForward: aws.Bool(false),
+	}
+
+	posts := []model.BlogPost{}
+	var unmarshalErr error
+	err := repo.client.QueryPages(queryInput, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		pagePosts, err := unmarshalPosts(page.Items)
+		if err != nil {
+			unmarshalErr = err
+			return false
+		}
+		posts = append(posts, pagePosts...)
+
+		return true
+	})
+	if err != nil {
+		return []model.BlogPost{}, translateError(err, nil)
+	}
+	if unmarshalErr != nil {
+		return []model.BlogPost{}, unmarshalErr
+	}
+
+	return posts, nil
+}
+
+// GetRevision searches and returns a previous revision of a blog post.
+func (repo *Repo) GetRevision(id string, revision int64) (model.BlogPost, bool, error) {
+	repo.createClient()
+
+	response, err := repo.client.GetItem(&dynamodb.GetItemInput{
+		TableName: aws.String(repo.revisionsTableName),
+		Key:       revisionItemKey(id, revision),
+	})
+	if err != nil {
+		return model.BlogPost{}, false, translateError(err, nil)
+	}
+	if len(response.Item) == 0 {
+		return model.BlogPost{}, false, nil
+	}
+
+	post, err := unmarshalPost(response.Item)
+	if err != nil {
+		return model.BlogPost{}, false, err
+	}
+
+	return post, true, nil
+}
+
+// putRevisionItem returns the transaction item that keeps a snapshot of a blog post's revision. Snapshots are never
+// overwritten.
+func (repo *Repo) putRevisionItem(post model.BlogPost) (*dynamodb.TransactWriteItem, error) {
+	item, err := dynamodbattribute.MarshalMap(post)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamodb.TransactWriteItem{
+		Put: &dynamodb.Put{
+			Item:                item,
+			TableName:           aws.String(repo.revisionsTableName),
+			ConditionExpression: aws.String("attribute_not_exists(revision)"),
+		},
+	}, nil
+}
+
+// removeRevisionItems deletes the snapshots of all previous revisions of a blog post.
+func (repo *Repo) removeRevisionItems(postID string) error {
+	revisions, err := repo.GetRevisions(postID)
+	if err != nil {
+		return err
+	}
+
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(revisions))
+	for _, revision := range revisions {
+		keys = append(keys, revisionItemKey(postID, revision.Revision))
+	}
+
+	return repo.batchDelete(repo.revisionsTableName, keys)
+}
+
+// revisionItemKey returns the key of the snapshot of a blog post's revision.
+func revisionItemKey(postID string, revision int64) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"id":       {S: aws.String(postID)},
+		"revision": {N: aws.String(strconv.FormatInt(revision, 10))},
+	}
+}
@@ -0,0 +1,298 @@
+package dynamodb
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
+)
+
+// GetAllByTag loads the first page of blog posts with a tag and a status from the database, sorted by their creation
+// timestamp.
+func (repo *Repo) GetAllByTag(tag string, status model.Status, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.queryWith(repo.tagQuery(tag, order), tag, nil, pageSize, repo.taggedPostsLoader(status))
+}
+
+// GetMoreByTag loads the page of blog posts with a tag and a status that starts where the page of the cursor ended,
+// sorted by their creation timestamp.
+func (repo *Repo) GetMoreByTag(tag string, status model.Status, cursor string, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	input := repo.tagQuery(tag, order)
+	startKey, err := repo.decodeCursor(*input.IndexName, tag, cursor)
+	if err != nil {
+		return []model.BlogPost{}, "", err
+	}
+
+	return repo.queryWith(input, tag, startKey, pageSize, repo.taggedPostsLoader(status))
+}
+
+// GetTagCounts loads every tag from the database together with the number of blog posts with a status that have it,
+// sorted by tag. The numbers come from the counters of the tags, which are kept up to date together with their index
+// items.
+func (repo *Repo) GetTagCounts(status model.Status) ([]model.TagCount, error) {
+	repo.createClient()
+
+	queryInput := &dynamodb.QueryInput{
+		TableName:                aws.String(repo.tagCountsTableName),
+		KeyConditionExpression:   aws.String("#status = :status"),
+		FilterExpression:         aws.String("postCount > :zero"),
+		ExpressionAttributeNames: map[string]*string{"#status": aws.String("status")},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":status": {S: aws.String(string(status))},
+			":zero":   {N: aws.String("0")},
+		},
+	}
+
+	tagCounts := []model.TagCount{}
+	var unmarshalErr error
+	err := repo.client.QueryPages(queryInput, func(page *dynamodb.QueryOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			count, err := strconv.ParseInt(aws.StringValue(item["postCount"].N), 10, 64)
+			if err != nil {
+				unmarshalErr = err
+				return false
+			}
+
+			tagCounts = append(tagCounts, model.TagCount{Tag: aws.StringValue(item["tag"].S), Count: count})
+		}
+
+		return true
+	})
+	if err != nil {
+		return []model.TagCount{}, translateError(err, nil)
+	}
+	if unmarshalErr != nil {
+		return []model.TagCount{}, unmarshalErr
+	}
+
+	return tagCounts, nil
+}
+
+// tagQuery returns the input of a query that lists the index items of a tag by their creation timestamp.
+func (repo *Repo) tagQuery(tag string, order generic.SortOrder) *dynamodb.QueryInput {
+	return &dynamodb.QueryInput{
+		TableName:                aws.String(repo.tagsTableName),
+		IndexName:                aws.String(tagIndex),
+		KeyConditionExpression:   aws.String("#tag = :tag"),
+		ExpressionAttributeNames: map[string]*string{"#tag": aws.String("tag")},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":tag": {
+				S: aws.String(tag),
+			},
+		},
+		ScanIndexForward: aws.Bool(order == generic.OldestFirst),
+	}
+}
+
+// taggedPostsLoader returns a function that loads the blog posts of a list of tag index items, in the same order,
+// and keeps the ones with a status.
+func (repo *Repo) taggedPostsLoader(status model.Status) func([]map[string]*dynamodb.AttributeValue) (
+	[]model.BlogPost, error) {
+	return func(items []map[string]*dynamodb.AttributeValue) ([]model.BlogPost, error) {
+		posts, err := repo.loadTaggedPosts(items)
+		if err != nil {
+			return []model.BlogPost{}, err
+		}
+
+		result := make([]model.BlogPost, 0, len(posts))
+		for _, post := range posts {
+			if post.HasStatus(status) {
+				result = append(result, post)
+			}
+		}
+
+		return result, nil
+	}
+}
+
+// loadTaggedPosts loads the blog posts of a list of tag index items, in the same order. Items whose blog post no
+// longer exists are skipped.
+func (repo *Repo) loadTaggedPosts(items []map[string]*dynamodb.AttributeValue) ([]model.BlogPost, error) {
+	if len(items) == 0 {
+		return []model.BlogPost{}, nil
+	}
+
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(items))
+	for _, item := range items {
+		keys = append(keys, map[string]*dynamodb.AttributeValue{"id": item["postId"]})
+	}
+
+	postsByID := map[string]model.BlogPost{}
+	requestItems := map[string]*dynamodb.KeysAndAttributes{repo.tableName: {Keys: keys}}
+	for len(requestItems) > 0 {
+		response, err := repo.client.BatchGetItem(&dynamodb.BatchGetItemInput{RequestItems: requestItems})
+		if err != nil {
+			return []model.BlogPost{}, translateError(err, nil)
+		}
+
+		posts, err := unmarshalPosts(response.Responses[repo.tableName])
+		if err != nil {
+			return []model.BlogPost{}, err
+		}
+		for _, post := range posts {
+			postsByID[post.ID] = post
+		}
+
+		requestItems = response.UnprocessedKeys
+	}
+
+	posts := make([]model.BlogPost, 0, len(items))
+	for _, item := range items {
+		if post, ok := postsByID[aws.StringValue(item["postId"].S)]; ok {
+			posts = append(posts, post)
+		}
+	}
+
+	return posts, nil
+}
+
+// putTagItems returns the transaction items that put the index items of a blog post's tags. The items also keep the
+// status of the blog post, so that the tags can be counted per status.
+func (repo *Repo) putTagItems(post model.BlogPost, creationTimestamp int64) []*dynamodb.TransactWriteItem {
+	status := &dynamodb.AttributeValue{S: aws.String(string(post.Status))}
+	if post.Status == "" {
+		status = &dynamodb.AttributeValue{NULL: aws.Bool(true)}
+	}
+
+	items := make([]*dynamodb.TransactWriteItem, 0, len(post.Tags))
+	for _, tag := range post.Tags {
+		items = append(items, &dynamodb.TransactWriteItem{
+			Put: &dynamodb.Put{
+				TableName: aws.String(repo.tagsTableName),
+				Item: map[string]*dynamodb.AttributeValue{
+					"tag":               {S: aws.String(tag)},
+					"postId":            {S: aws.String(post.ID)},
+					"creationTimestamp": {N: aws.String(strconv.FormatInt(creationTimestamp, 10))},
+					"status":            status,
+				},
+			},
+		})
+	}
+
+	return items
+}
+
+// deleteTagItems returns the transaction items that delete the index items of a blog post's tags.
+func (repo *Repo) deleteTagItems(postID string, tags model.Tags) []*dynamodb.TransactWriteItem {
+	items := make([]*dynamodb.TransactWriteItem, 0, len(tags))
+	for _, tag := range tags {
+		items = append(items, &dynamodb.TransactWriteItem{
+			Delete: &dynamodb.Delete{
+				TableName: aws.String(repo.tagsTableName),
+				Key:       tagItemKey(postID, tag),
+			},
+		})
+	}
+
+	return items
+}
+
+// removeTags deletes the index items of a blog post's tags and decreases their counters for the status, in a single
+// transaction.
+func (repo *Repo) removeTags(postID string, tags model.Tags, status model.Status) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	transactItems := repo.deleteTagItems(postID, tags)
+	transactItems = append(transactItems, repo.updateTagCounts(tagCountDeltas(tags, status, nil, ""))...)
+
+	_, err := repo.client.TransactWriteItems(&dynamodb.TransactWriteItemsInput{TransactItems: transactItems})
+
+	return translateError(err, nil)
+}
+
+// tagCounter identifies the counter of the blog posts with a status that have a tag.
+type tagCounter struct {
+	status model.Status
+	tag    string
+}
+
+// tagCountDeltas returns the changes to the tag counters when the tags and the status of a blog post change. Tags that
+// keep their status don't change any counter, so that each counter is changed at most once in a transaction.
+func tagCountDeltas(oldTags model.Tags, oldStatus model.Status, newTags model.Tags,
+	newStatus model.Status) map[tagCounter]int64 {
+	deltas := map[tagCounter]int64{}
+	for _, tag := range oldTags {
+		deltas[tagCounter{status: oldStatus, tag: tag}]--
+	}
+	for _, tag := range newTags {
+		deltas[tagCounter{status: newStatus, tag: tag}]++
+	}
+
+	for counter, delta := range deltas {
+		if delta == 0 {
+			delete(deltas, counter)
+		}
+	}
+
+	return deltas
+}
+
+// updateTagCounts returns the transaction items that add the deltas to the tag counters, sorted by status and tag.
+// Counters that don't exist start from zero.
+func (repo *Repo) updateTagCounts(deltas map[tagCounter]int64) []*dynamodb.TransactWriteItem {
+	counters := make([]tagCounter, 0, len(deltas))
+	for counter := range deltas {
+		counters = append(counters, counter)
+	}
+	sort.Slice(counters, func(i, j int) bool {
+		if counters[i].status != counters[j].status {
+			return counters[i].status < counters[j].status
+		}
+		return counters[i].tag < counters[j].tag
+	})
+
+	items := make([]*dynamodb.TransactWriteItem, 0, len(counters))
+	for _, counter := range counters {
+		items = append(items, &dynamodb.TransactWriteItem{
+			Update: &dynamodb.Update{
+				TableName: aws.String(repo.tagCountsTableName),
+				Key: map[string]*dynamodb.AttributeValue{
+					"status": {S: aws.String(string(counter.status))},
+					"tag":    {S: aws.String(counter.tag)},
+				},
+				UpdateExpression: aws.String("ADD postCount :delta"),
+				ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+					":delta": {N: aws.String(strconv.FormatInt(deltas[counter], 10))},
+				},
+			},
+		})
+	}
+
+	return items
+}
+
+// countedTags returns the tags of a blog post that have index items and are counted. Blog posts from before the tag
+// list keep their tags in a single string and have neither.
+func countedTags(item map[string]*dynamodb.AttributeValue, post model.BlogPost) model.Tags {
+	if tags, ok := item["tags"]; ok && tags.S != nil {
+		return model.Tags{}
+	}
+
+	return post.Tags
+}
+
+// tagItemKey returns the key of the index item of a blog post's tag.
+func tagItemKey(postID string, tag string) map[string]*dynamodb.AttributeValue {
+	return map[string]*dynamodb.AttributeValue{
+		"tag":    {S: aws.String(tag)},
+		"postId": {S: aws.String(postID)},
+	}
+}
+
+// removedTags returns the old tags that are not among the new ones.
+func removedTags(oldTags model.Tags, newTags model.Tags) model.Tags {
+	removed := model.Tags{}
+	for _, tag := range oldTags {
+		if !newTags.Contains(tag) {
+			removed = append(removed, tag)
+		}
+	}
+
+	return removed
+}
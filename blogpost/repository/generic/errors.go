@@ -0,0 +1,20 @@
+package generic
+
+import "errors"
+
+// The errors that a repository returns (possibly wrapped) when an operation fails for a known reason. They allow the
+// service layer to react to failures without knowing which database is used.
+var (
+	// ErrAlreadyExists is returned when a blog post is created with an id that is already used.
+	ErrAlreadyExists = errors.New("the blog post already exists")
+	// ErrRevisionMismatch is returned when a blog post is updated but the stored revision is different.
+	ErrRevisionMismatch = errors.New("the blog post has a different revision")
+	// ErrNotFound is returned when an operation requires a blog post that doesn't exist.
+	ErrNotFound = errors.New("the blog post was not found")
+	// ErrThrottled is returned when the database rejects the operation because of too many requests.
+	ErrThrottled = errors.New("the operation was throttled")
+	// ErrTooLarge is returned when the blog post exceeds the size that the database can store.
+	ErrTooLarge = errors.New("the blog post is too large")
+	// ErrInvalidCursor is returned when a pagination cursor is malformed or was not issued by the repository.
+	ErrInvalidCursor = errors.New("the cursor is not valid")
+)
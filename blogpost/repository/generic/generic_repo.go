@@ -2,12 +2,51 @@ package generic
 
 import "github.com/printezisn/serverless-blog-back/blogpost/model"
 
+// SortOrder represents the order in which blog posts are listed, based on their creation timestamp.
+type SortOrder int
+
+const (
+	// NewestFirst lists the most recently created blog posts first.
+	NewestFirst SortOrder = iota
+	// OldestFirst lists the least recently created blog posts first.
+	OldestFirst
+)
+
 // Repo represents the repository layer for blog posts.
+//
+// GetAll and GetMore return up to pageSize blog posts with a status, sorted by their creation timestamp, together with
+// an opaque cursor that GetMore accepts to load the next page. The cursor is empty when there are no more blog posts.
+// GetMore must be called with the same status and order that were used to create the cursor. Blog posts without a
+// status are considered published. GetAllByCategory and GetMoreByCategory work the same way, but only for the blog
+// posts of a category, GetAllByAuthor and GetMoreByAuthor only for the blog posts of an author, and GetAllByTag and
+// GetMoreByTag only for the blog posts with a tag; their cursors are rejected with ErrInvalidCursor for another
+// category, author or tag. GetTagCounts returns every tag with the number of blog posts with a status that have it,
+// sorted by tag. GetDue returns up to pageSize drafts that are scheduled to be published at or before a timestamp,
+// earliest first, and GetDeleted does the same for the blog posts that were moved to the trash at or before a
+// timestamp. Every successful Update keeps a snapshot of the revision that it replaced; GetRevisions returns the
+// snapshots of a blog post, newest first, and GetRevision returns a single one. Delete only deletes a blog post that
+// still has the given revision and removes its snapshots too.
 type Repo interface {
 	Create(post model.BlogPost) (model.BlogPost, error)
 	Update(revision int64, post model.BlogPost) (model.BlogPost, error)
 	Get(id string) (model.BlogPost, bool, error)
-	Delete(id string) (bool, error)
-	GetAll(pageSize int64) ([]model.BlogPost, error)
-	GetMore(lastID string, pageSize int64) ([]model.BlogPost, error)
+	Delete(revision int64, id string) (bool, error)
+	GetAll(status model.Status, order SortOrder, pageSize int64) ([]model.BlogPost, string, error)
+	GetMore(status model.Status, cursor string, order SortOrder, pageSize int64) ([]model.BlogPost, string, error)
+	GetAllByCategory(category string, status model.Status, order SortOrder,
+		pageSize int64) ([]model.BlogPost, string, error)
+	GetMoreByCategory(category string, status model.Status, cursor string, order SortOrder,
+		pageSize int64) ([]model.BlogPost, string, error)
+	GetAllByAuthor(authorID string, status model.Status, order SortOrder,
+		pageSize int64) ([]model.BlogPost, string, error)
+	GetMoreByAuthor(authorID string, status model.Status, cursor string, order SortOrder,
+		pageSize int64) ([]model.BlogPost, string, error)
+	GetAllByTag(tag string, status model.Status, order SortOrder, pageSize int64) ([]model.BlogPost, string, error)
+	GetMoreByTag(tag string, status model.Status, cursor string, order SortOrder,
+		pageSize int64) ([]model.BlogPost, string, error)
+	GetTagCounts(status model.Status) ([]model.TagCount, error)
+	GetDue(timestamp int64, pageSize int64) ([]model.BlogPost, error)
+	GetDeleted(timestamp int64, pageSize int64) ([]model.BlogPost, error)
+	GetRevisions(id string) ([]model.BlogPost, error)
+	GetRevision(id string, revision int64) (model.BlogPost, bool, error)
 }
@@ -0,0 +1,385 @@
+package memory
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
+	"github.com/printezisn/serverless-blog-back/global/cursor"
+)
+
+// Repo represents a repository for blog posts that keeps everything in memory. It is safe for concurrent use.
+type Repo struct {
+	mutex   *sync.RWMutex
+	posts   map[string]model.BlogPost
+	history map[string][]model.BlogPost
+	cursors cursor.Codec
+}
+
+// New returns a new repository instance for blog posts that keeps everything in memory.
+func New() Repo {
+	return Repo{
+		mutex:   &sync.RWMutex{},
+		posts:   map[string]model.BlogPost{},
+		history: map[string][]model.BlogPost{},
+		cursors: cursor.New(),
+	}
+}
+
+// Create creates a new blog post in memory.
+func (repo *Repo) Create(post model.BlogPost) (model.BlogPost, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	if _, ok := repo.posts[post.ID]; ok {
+		return post, generic.ErrAlreadyExists
+	}
+
+	repo.posts[post.ID] = post
+
+	return post, nil
+}
+
+// Update updates an existing blog post in memory. The previous revision of the blog post is kept as a snapshot.
+func (repo *Repo) Update(revision int64, post model.BlogPost) (model.BlogPost, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	existingPost, ok := repo.posts[post.ID]
+	if !ok {
+		return model.BlogPost{}, generic.ErrNotFound
+	}
+	if existingPost.Revision != revision {
+		return model.BlogPost{}, generic.ErrRevisionMismatch
+	}
+	repo.history[post.ID] = append(repo.history[post.ID], existingPost)
+
+	existingPost.Title = post.Title
+	existingPost.Description = post.Description
+	existingPost.Tags = post.Tags
+	existingPost.Body = post.Body
+	existingPost.Template = post.Template
+	existingPost.Category = post.Category
+	existingPost.UpdateTimestamp = post.UpdateTimestamp
+	existingPost.Revision = post.Revision
+	existingPost.Status = post.Status
+	existingPost.PublishedTimestamp = post.PublishedTimestamp
+	existingPost.ScheduledAt = post.ScheduledAt
+	existingPost.DeletedTimestamp = post.DeletedTimestamp
+	repo.posts[post.ID] = existingPost
+
+	return existingPost, nil
+}
+
+// Get searches and returns a blog post based on its id.
+func (repo *Repo) Get(id string) (model.BlogPost, bool, error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	post, ok := repo.posts[id]
+
+	return post, ok, nil
+}
+
+// GetAll loads the first page of blog posts with a status, sorted by their creation timestamp.
+func (repo *Repo) GetAll(status model.Status, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.list(status, "", matchAll, nil, order, pageSize)
+}
+
+// GetMore loads the page of blog posts with a status that follows the page of the cursor, sorted by their creation
+// timestamp.
+func (repo *Repo) GetMore(status model.Status, cursor string, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	last, err := repo.decodeCursor("", cursor)
+	if err != nil {
+		return []model.BlogPost{}, "", err
+	}
+
+	return repo.list(status, "", matchAll, &last, order, pageSize)
+}
+
+// GetAllByCategory loads the first page of blog posts of a category with a status, sorted by their creation
+// timestamp.
+func (repo *Repo) GetAllByCategory(category string, status model.Status, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.list(status, "category/"+category, matchCategory(category), nil, order, pageSize)
+}
+
+// GetMoreByCategory loads the page of blog posts of a category with a status that follows the page of the cursor,
+// sorted by their creation timestamp.
+func (repo *Repo) GetMoreByCategory(category string, status model.Status, cursor string, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	last, err := repo.decodeCursor("category/"+category, cursor)
+	if err != nil {
+		return []model.BlogPost{}, "", err
+	}
+
+	return repo.list(status, "category/"+category, matchCategory(category), &last, order, pageSize)
+}
+
+// GetAllByAuthor loads the first page of blog posts of an author with a status, sorted by their creation timestamp.
+func (repo *Repo) GetAllByAuthor(authorID string, status model.Status, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.list(status, "author/"+authorID, matchAuthor(authorID), nil, order, pageSize)
+}
+
+// GetMoreByAuthor loads the page of blog posts of an author with a status that follows the page of the cursor, sorted
+// by their creation timestamp.
+func (repo *Repo) GetMoreByAuthor(authorID string, status model.Status, cursor string, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	last, err := repo.decodeCursor("author/"+authorID, cursor)
+	if err != nil {
+		return []model.BlogPost{}, "", err
+	}
+
+	return repo.list(status, "author/"+authorID, matchAuthor(authorID), &last, order, pageSize)
+}
+
+// GetAllByTag loads the first page of blog posts with a tag and a status, sorted by their creation timestamp.
+func (repo *Repo) GetAllByTag(tag string, status model.Status, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	return repo.list(status, "tag/"+tag, matchTag(tag), nil, order, pageSize)
+}
+
+// GetMoreByTag loads the page of blog posts with a tag and a status that follows the page of the cursor, sorted by
+// their creation timestamp.
+func (repo *Repo) GetMoreByTag(tag string, status model.Status, cursor string, order generic.SortOrder,
+	pageSize int64) ([]model.BlogPost, string, error) {
+	last, err := repo.decodeCursor("tag/"+tag, cursor)
+	if err != nil {
+		return []model.BlogPost{}, "", err
+	}
+
+	return repo.list(status, "tag/"+tag, matchTag(tag), &last, order, pageSize)
+}
+
+// GetTagCounts returns every tag together with the number of blog posts with a status that have it, sorted by tag.
+func (repo *Repo) GetTagCounts(status model.Status) ([]model.TagCount, error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	counts := map[string]int64{}
+	for _, post := range repo.posts {
+		if !post.HasStatus(status) {
+			continue
+		}
+		for _, tag := range post.Tags {
+			counts[tag]++
+		}
+	}
+
+	tagCounts := make([]model.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tagCounts = append(tagCounts, model.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tagCounts, func(i, j int) bool { return tagCounts[i].Tag < tagCounts[j].Tag })
+
+	return tagCounts, nil
+}
+
+// GetDue loads up to pageSize drafts that are scheduled to be published at or before the timestamp, earliest first.
+func (repo *Repo) GetDue(timestamp int64, pageSize int64) ([]model.BlogPost, error) {
+	return repo.listUntil(timestamp, pageSize, func(post model.BlogPost) int64 {
+		if !post.HasStatus(model.StatusDraft) {
+			return 0
+		}
+		return post.ScheduledAt
+	})
+}
+
+// GetDeleted loads up to pageSize blog posts that were moved to the trash at or before the timestamp, earliest first.
+func (repo *Repo) GetDeleted(timestamp int64, pageSize int64) ([]model.BlogPost, error) {
+	return repo.listUntil(timestamp, pageSize, func(post model.BlogPost) int64 {
+		if !post.HasStatus(model.StatusDeleted) {
+			return 0
+		}
+		return post.DeletedTimestamp
+	})
+}
+
+// listUntil returns up to pageSize blog posts whose time is set and is at or before the timestamp, earliest first (and
+// by id, to break ties). A time of 0 means that it's not set.
+func (repo *Repo) listUntil(timestamp int64, pageSize int64, timeOf func(model.BlogPost) int64) ([]model.BlogPost,
+	error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	posts := []model.BlogPost{}
+	for _, post := range repo.posts {
+		if time := timeOf(post); time > 0 && time <= timestamp {
+			posts = append(posts, post)
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		if timeOf(posts[i]) == timeOf(posts[j]) {
+			return posts[i].ID < posts[j].ID
+		}
+		return timeOf(posts[i]) < timeOf(posts[j])
+	})
+
+	if int64(len(posts)) > pageSize {
+		posts = posts[:pageSize]
+	}
+
+	return posts, nil
+}
+
+// GetRevisions loads the previous revisions of a blog post, newest first.
+func (repo *Repo) GetRevisions(id string) ([]model.BlogPost, error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	history := repo.history[id]
+	posts := make([]model.BlogPost, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		posts = append(posts, history[i])
+	}
+
+	return posts, nil
+}
+
+// GetRevision searches and returns a previous revision of a blog post.
+func (repo *Repo) GetRevision(id string, revision int64) (model.BlogPost, bool, error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	for _, post := range repo.history[id] {
+		if post.Revision == revision {
+			return post, true, nil
+		}
+	}
+
+	return model.BlogPost{}, false, nil
+}
+
+// Delete deletes a blog post from memory, together with its revisions, if it still has the revision.
+func (repo *Repo) Delete(revision int64, id string) (bool, error) {
+	repo.mutex.Lock()
+	defer repo.mutex.Unlock()
+
+	existingPost, ok := repo.posts[id]
+	if !ok {
+		return false, nil
+	}
+	if existingPost.Revision != revision {
+		return false, generic.ErrRevisionMismatch
+	}
+
+	delete(repo.posts, id)
+	delete(repo.history, id)
+
+	return true, nil
+}
+
+// list returns up to pageSize blog posts with the status that match the filter and come after the last position,
+// sorted by their creation timestamp (and id, to break ties). It also returns the cursor of the next page, if there
+// are more posts, which only continues the same listing.
+func (repo *Repo) list(status model.Status, listing string, filter func(model.BlogPost) bool, last *position,
+	order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	repo.mutex.RLock()
+	defer repo.mutex.RUnlock()
+
+	posts := []model.BlogPost{}
+	for _, post := range repo.posts {
+		if post.HasStatus(status) && filter(post) && (last == nil || last.before(positionOf(post), order)) {
+			posts = append(posts, post)
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		return positionOf(posts[i]).before(positionOf(posts[j]), order)
+	})
+
+	if int64(len(posts)) <= pageSize {
+		return posts, "", nil
+	}
+
+	posts = posts[:pageSize]
+	next := positionOf(posts[len(posts)-1])
+	next.Listing = listing
+	cursor, err := repo.encodeCursor(next)
+
+	return posts, cursor, err
+}
+
+// encodeCursor turns the position of the last blog post of a page into a cursor.
+func (repo *Repo) encodeCursor(last position) (string, error) {
+	payload, err := json.Marshal(last)
+	if err != nil {
+		return "", err
+	}
+
+	return repo.cursors.Encode(payload), nil
+}
+
+// decodeCursor turns a cursor back into the position of the last blog post of a page. Cursors that were created for
+// another listing are rejected.
+func (repo *Repo) decodeCursor(listing string, cursor string) (position, error) {
+	payload, err := repo.cursors.Decode(cursor)
+	if err != nil {
+		return position{}, fmt.Errorf("%w: %v", generic.ErrInvalidCursor, err)
+	}
+
+	var last position
+	if err = json.Unmarshal(payload, &last); err != nil || last.ID == "" || last.Listing != listing {
+		return position{}, generic.ErrInvalidCursor
+	}
+
+	return last, nil
+}
+
+// position represents the place of a blog post in a listing, e.g. "category/news" for the blog posts of a category,
+// or an empty one for all blog posts.
+type position struct {
+	Listing           string `json:"listing,omitempty"`
+	ID                string `json:"id"`
+	CreationTimestamp int64  `json:"creationTimestamp"`
+}
+
+// positionOf returns the position of a blog post.
+func positionOf(post model.BlogPost) position {
+	return position{ID: post.ID, CreationTimestamp: post.CreationTimestamp}
+}
+
+// before checks if a position comes before another one in the given order.
+func (p position) before(other position, order generic.SortOrder) bool {
+	if p.CreationTimestamp == other.CreationTimestamp {
+		if order == generic.OldestFirst {
+			return p.ID < other.ID
+		}
+		return p.ID > other.ID
+	}
+	if order == generic.OldestFirst {
+		return p.CreationTimestamp < other.CreationTimestamp
+	}
+	return p.CreationTimestamp > other.CreationTimestamp
+}
+
+// matchAll is a filter that matches every blog post.
+func matchAll(model.BlogPost) bool {
+	return true
+}
+
+// matchCategory returns a filter that matches the blog posts of a category.
+func matchCategory(category string) func(model.BlogPost) bool {
+	return func(post model.BlogPost) bool {
+		return post.Category == category
+	}
+}
+
+// matchAuthor returns a filter that matches the blog posts of an author.
+func matchAuthor(authorID string) func(model.BlogPost) bool {
+	return func(post model.BlogPost) bool {
+		return post.AuthorID == authorID
+	}
+}
+
+// matchTag returns a filter that matches the blog posts with a tag.
+func matchTag(tag string) func(model.BlogPost) bool {
+	return func(post model.BlogPost) bool {
+		return post.Tags.Contains(tag)
+	}
+}
@@ -0,0 +1,119 @@
+package memory
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/repotest"
+)
+
+// TestRepo runs the repository test suite against the in-memory repository.
+func TestRepo(t *testing.T) {
+	repotest.Run(t, func() generic.Repo {
+		repo := New()
+		return &repo
+	})
+}
+
+// TestCreateWithExistingID tests that the Create method returns ErrAlreadyExists when the id is taken.
+func TestCreateWithExistingID(t *testing.T) {
+	repo := New()
+	post := model.BlogPost{ID: "id", Title: "title", Revision: 1}
+
+	if _, err := repo.Create(post); err != nil {
+		t.Fatal("The first creation was expected to succeed, but it failed with ", err)
+	}
+
+	_, err := repo.Create(post)
+	if !errors.Is(err, generic.ErrAlreadyExists) {
+		t.Error("The error was expected to be ErrAlreadyExists, but it was ", err)
+	}
+}
+
+// TestUpdateWithWrongRevision tests that the Update method returns ErrRevisionMismatch when the stored revision is
+// different.
+func TestUpdateWithWrongRevision(t *testing.T) {
+	repo := New()
+	post := model.BlogPost{ID: "id", Title: "title", Revision: 1}
+	repo.Create(post)
+
+	post.Revision = 3
+	_, err := repo.Update(2, post)
+	if !errors.Is(err, generic.ErrRevisionMismatch) {
+		t.Error("The error was expected to be ErrRevisionMismatch, but it was ", err)
+	}
+}
+
+// TestUpdateWithSuccess tests that the Update method keeps the creation timestamp and stores the new values.
+func TestUpdateWithSuccess(t *testing.T) {
+	repo := New()
+	repo.Create(model.BlogPost{ID: "id", Title: "title", Revision: 1, CreationTimestamp: 5})
+
+	updatedPost, err := repo.Update(1, model.BlogPost{ID: "id", Title: "title2", Revision: 2, UpdateTimestamp: 6})
+	if err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+
+	expectedPost := model.BlogPost{ID: "id", Title: "title2", Revision: 2, CreationTimestamp: 5, UpdateTimestamp: 6}
+	if !updatedPost.Equal(expectedPost) {
+		t.Error("The post was expected to be ", expectedPost, " but it was ", updatedPost)
+	}
+
+	storedPost, _, _ := repo.Get("id")
+	if !storedPost.Equal(expectedPost) {
+		t.Error("The stored post was expected to be ", expectedPost, " but it was ", storedPost)
+	}
+}
+
+// TestGetMoreWithSameCreationTimestamp tests that blog posts with the same creation timestamp are sorted by their id.
+func TestGetMoreWithSameCreationTimestamp(t *testing.T) {
+	repo := New()
+	for _, id := range []string{"c", "a", "d", "b", "e"} {
+		repo.Create(model.BlogPost{ID: id, Revision: 1, CreationTimestamp: 1})
+	}
+
+	posts, cursor, _ := repo.GetAll(model.StatusPublished, generic.OldestFirst, 2)
+	ids := []string{}
+	for {
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+		}
+		if cursor == "" {
+			break
+		}
+		posts, cursor, _ = repo.GetMore(model.StatusPublished, cursor, generic.OldestFirst, 2)
+	}
+
+	if fmt.Sprint(ids) != "[a b c d e]" {
+		t.Error("The ids were expected to be [a b c d e], but they were ", ids)
+	}
+}
+
+// TestConcurrentAccess tests that the repository can be used from multiple goroutines.
+func TestConcurrentAccess(t *testing.T) {
+	repo := New()
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			id := fmt.Sprintf("id%d", i)
+			repo.Create(model.BlogPost{ID: id, Revision: 1})
+			repo.Get(id)
+			repo.GetAll(model.StatusPublished, generic.NewestFirst, 10)
+			repo.Update(1, model.BlogPost{ID: id, Revision: 2})
+		}(i)
+	}
+	wg.Wait()
+
+	posts, _, _ := repo.GetAll(model.StatusPublished, generic.NewestFirst, 100)
+	if len(posts) != 50 {
+		t.Errorf("The number of posts was expected to be 50, but it was %d.", len(posts))
+	}
+}
@@ -2,6 +2,7 @@
 
 package mocks
 
+import generic "github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
 import mock "github.com/stretchr/testify/mock"
 import model "github.com/printezisn/serverless-blog-back/blogpost/model"
 
@@ -31,20 +32,20 @@ func (_m *Repo) Create(post model.BlogPost) (model.BlogPost, error) {
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *Repo) Delete(id string) (bool, error) {
-	ret := _m.Called(id)
+// Delete provides a mock function with given fields: revision, id
+func (_m *Repo) Delete(revision int64, id string) (bool, error) {
+	ret := _m.Called(revision, id)
 
 	var r0 bool
-	if rf, ok := ret.Get(0).(func(string) bool); ok {
-		r0 = rf(id)
+	if rf, ok := ret.Get(0).(func(int64, string) bool); ok {
+		r0 = rf(revision, id)
 	} else {
 		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(id)
+	if rf, ok := ret.Get(1).(func(int64, string) error); ok {
+		r1 = rf(revision, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -80,13 +81,156 @@ func (_m *Repo) Get(id string) (model.BlogPost, bool, error) {
 	return r0, r1, r2
 }
 
-// GetAll provides a mock function with given fields: pageSize
-func (_m *Repo) GetAll(pageSize int64) ([]model.BlogPost, error) {
-	ret := _m.Called(pageSize)
+// GetAll provides a mock function with given fields: status, order, pageSize
+func (_m *Repo) GetAll(status model.Status, order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	ret := _m.Called(status, order, pageSize)
 
 	var r0 []model.BlogPost
-	if rf, ok := ret.Get(0).(func(int64) []model.BlogPost); ok {
-		r0 = rf(pageSize)
+	if rf, ok := ret.Get(0).(func(model.Status, generic.SortOrder, int64) []model.BlogPost); ok {
+		r0 = rf(status, order, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(model.Status, generic.SortOrder, int64) string); ok {
+		r1 = rf(status, order, pageSize)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(model.Status, generic.SortOrder, int64) error); ok {
+		r2 = rf(status, order, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAllByAuthor provides a mock function with given fields: authorID, status, order, pageSize
+func (_m *Repo) GetAllByAuthor(authorID string, status model.Status, order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	ret := _m.Called(authorID, status, order, pageSize)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(string, model.Status, generic.SortOrder, int64) []model.BlogPost); ok {
+		r0 = rf(authorID, status, order, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, model.Status, generic.SortOrder, int64) string); ok {
+		r1 = rf(authorID, status, order, pageSize)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, model.Status, generic.SortOrder, int64) error); ok {
+		r2 = rf(authorID, status, order, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAllByCategory provides a mock function with given fields: category, status, order, pageSize
+func (_m *Repo) GetAllByCategory(category string, status model.Status, order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	ret := _m.Called(category, status, order, pageSize)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(string, model.Status, generic.SortOrder, int64) []model.BlogPost); ok {
+		r0 = rf(category, status, order, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, model.Status, generic.SortOrder, int64) string); ok {
+		r1 = rf(category, status, order, pageSize)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, model.Status, generic.SortOrder, int64) error); ok {
+		r2 = rf(category, status, order, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetAllByTag provides a mock function with given fields: tag, status, order, pageSize
+func (_m *Repo) GetAllByTag(tag string, status model.Status, order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	ret := _m.Called(tag, status, order, pageSize)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(string, model.Status, generic.SortOrder, int64) []model.BlogPost); ok {
+		r0 = rf(tag, status, order, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, model.Status, generic.SortOrder, int64) string); ok {
+		r1 = rf(tag, status, order, pageSize)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, model.Status, generic.SortOrder, int64) error); ok {
+		r2 = rf(tag, status, order, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetDeleted provides a mock function with given fields: timestamp, pageSize
+func (_m *Repo) GetDeleted(timestamp int64, pageSize int64) ([]model.BlogPost, error) {
+	ret := _m.Called(timestamp, pageSize)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(int64, int64) []model.BlogPost); ok {
+		r0 = rf(timestamp, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(timestamp, pageSize)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDue provides a mock function with given fields: timestamp, pageSize
+func (_m *Repo) GetDue(timestamp int64, pageSize int64) ([]model.BlogPost, error) {
+	ret := _m.Called(timestamp, pageSize)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(int64, int64) []model.BlogPost); ok {
+		r0 = rf(timestamp, pageSize)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.BlogPost)
@@ -94,8 +238,8 @@ func (_m *Repo) GetAll(pageSize int64) ([]model.BlogPost, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(pageSize)
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(timestamp, pageSize)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -103,22 +247,193 @@ func (_m *Repo) GetAll(pageSize int64) ([]model.BlogPost, error) {
 	return r0, r1
 }
 
-// GetMore provides a mock function with given fields: lastID, pageSize
-func (_m *Repo) GetMore(lastID string, pageSize int64) ([]model.BlogPost, error) {
-	ret := _m.Called(lastID, pageSize)
+// GetMore provides a mock function with given fields: status, cursor, order, pageSize
+func (_m *Repo) GetMore(status model.Status, cursor string, order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	ret := _m.Called(status, cursor, order, pageSize)
 
 	var r0 []model.BlogPost
-	if rf, ok := ret.Get(0).(func(string, int64) []model.BlogPost); ok {
-		r0 = rf(lastID, pageSize)
+	if rf, ok := ret.Get(0).(func(model.Status, string, generic.SortOrder, int64) []model.BlogPost); ok {
+		r0 = rf(status, cursor, order, pageSize)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]model.BlogPost)
 		}
 	}
 
+	var r1 string
+	if rf, ok := ret.Get(1).(func(model.Status, string, generic.SortOrder, int64) string); ok {
+		r1 = rf(status, cursor, order, pageSize)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(model.Status, string, generic.SortOrder, int64) error); ok {
+		r2 = rf(status, cursor, order, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetMoreByAuthor provides a mock function with given fields: authorID, status, cursor, order, pageSize
+func (_m *Repo) GetMoreByAuthor(authorID string, status model.Status, cursor string, order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	ret := _m.Called(authorID, status, cursor, order, pageSize)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(string, model.Status, string, generic.SortOrder, int64) []model.BlogPost); ok {
+		r0 = rf(authorID, status, cursor, order, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, model.Status, string, generic.SortOrder, int64) string); ok {
+		r1 = rf(authorID, status, cursor, order, pageSize)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, model.Status, string, generic.SortOrder, int64) error); ok {
+		r2 = rf(authorID, status, cursor, order, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetMoreByCategory provides a mock function with given fields: category, status, cursor, order, pageSize
+func (_m *Repo) GetMoreByCategory(category string, status model.Status, cursor string, order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	ret := _m.Called(category, status, cursor, order, pageSize)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(string, model.Status, string, generic.SortOrder, int64) []model.BlogPost); ok {
+		r0 = rf(category, status, cursor, order, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, model.Status, string, generic.SortOrder, int64) string); ok {
+		r1 = rf(category, status, cursor, order, pageSize)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, model.Status, string, generic.SortOrder, int64) error); ok {
+		r2 = rf(category, status, cursor, order, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetMoreByTag provides a mock function with given fields: tag, status, cursor, order, pageSize
+func (_m *Repo) GetMoreByTag(tag string, status model.Status, cursor string, order generic.SortOrder, pageSize int64) ([]model.BlogPost, string, error) {
+	ret := _m.Called(tag, status, cursor, order, pageSize)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(string, model.Status, string, generic.SortOrder, int64) []model.BlogPost); ok {
+		r0 = rf(tag, status, cursor, order, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 string
+	if rf, ok := ret.Get(1).(func(string, model.Status, string, generic.SortOrder, int64) string); ok {
+		r1 = rf(tag, status, cursor, order, pageSize)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, model.Status, string, generic.SortOrder, int64) error); ok {
+		r2 = rf(tag, status, cursor, order, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRevision provides a mock function with given fields: id, revision
+func (_m *Repo) GetRevision(id string, revision int64) (model.BlogPost, bool, error) {
+	ret := _m.Called(id, revision)
+
+	var r0 model.BlogPost
+	if rf, ok := ret.Get(0).(func(string, int64) model.BlogPost); ok {
+		r0 = rf(id, revision)
+	} else {
+		r0 = ret.Get(0).(model.BlogPost)
+	}
+
+	var r1 bool
+	if rf, ok := ret.Get(1).(func(string, int64) bool); ok {
+		r1 = rf(id, revision)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(string, int64) error); ok {
+		r2 = rf(id, revision)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetRevisions provides a mock function with given fields: id
+func (_m *Repo) GetRevisions(id string) ([]model.BlogPost, error) {
+	ret := _m.Called(id)
+
+	var r0 []model.BlogPost
+	if rf, ok := ret.Get(0).(func(string) []model.BlogPost); ok {
+		r0 = rf(id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.BlogPost)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(string) error); ok {
+		r1 = rf(id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTagCounts provides a mock function with given fields: status
+func (_m *Repo) GetTagCounts(status model.Status) ([]model.TagCount, error) {
+	ret := _m.Called(status)
+
+	var r0 []model.TagCount
+	if rf, ok := ret.Get(0).(func(model.Status) []model.TagCount); ok {
+		r0 = rf(status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]model.TagCount)
+		}
+	}
+
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, int64) error); ok {
-		r1 = rf(lastID, pageSize)
+	if rf, ok := ret.Get(1).(func(model.Status) error); ok {
+		r1 = rf(status)
 	} else {
 		r1 = ret.Error(1)
 	}
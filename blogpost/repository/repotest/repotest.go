@@ -0,0 +1,958 @@
+// Package repotest contains a test suite that every implementation of the blog post repository must pass.
+package repotest
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
+)
+
+// Factory returns a new repository instance without any stored blog posts.
+type Factory func() generic.Repo
+
+// Run runs the whole test suite against the repositories returned by the factory. Every test gets its own
+// repository instance.
+func Run(t *testing.T, factory Factory) {
+	tests := []struct {
+		name string
+		test func(*testing.T, generic.Repo)
+	}{
+		{"CreateWithSuccess", testCreateWithSuccess},
+		{"CreateWithExistingID", testCreateWithExistingID},
+		{"GetKeyAfterUpdate", testGetKeyAfterUpdate},
+		{"UpdateWithSuccess", testUpdateWithSuccess},
+		{"UpdateWithWrongRevision", testUpdateWithWrongRevision},
+		{"UpdateWithMissingPost", testUpdateWithMissingPost},
+		{"GetWithMissingPost", testGetWithMissingPost},
+		{"DeleteWithExistingPost", testDeleteWithExistingPost},
+		{"DeleteWithMissingPost", testDeleteWithMissingPost},
+		{"DeleteWithWrongRevision", testDeleteWithWrongRevision},
+		{"GetAllWithNoPosts", testGetAllWithNoPosts},
+		{"GetAllWithFewPosts", testGetAllWithFewPosts},
+		{"GetMoreWithPaging", testGetMoreWithPaging},
+		{"GetAllAfterUpdate", testGetAllAfterUpdate},
+		{"GetMoreAfterLastPost", testGetMoreAfterLastPost},
+		{"GetMoreWithInvalidCursor", testGetMoreWithInvalidCursor},
+		{"GetMoreByCategoryWithPaging", testGetMoreByCategoryWithPaging},
+		{"GetAllByCategoryWithMissingCategory", testGetAllByCategoryWithMissingCategory},
+		{"GetMoreByCategoryAfterUpdate", testGetMoreByCategoryAfterUpdate},
+		{"GetMoreByAuthorWithPaging", testGetMoreByAuthorWithPaging},
+		{"GetMoreWithCursorOfOtherPartition", testGetMoreWithCursorOfOtherPartition},
+		{"GetMoreByTagWithPaging", testGetMoreByTagWithPaging},
+		{"GetMoreByTagAfterUpdate", testGetMoreByTagAfterUpdate},
+		{"GetMoreByTagAfterLastPost", testGetMoreByTagAfterLastPost},
+		{"GetAllByTagAfterDelete", testGetAllByTagAfterDelete},
+		{"GetTagCounts", testGetTagCounts},
+		{"GetTagCountsAfterUpdate", testGetTagCountsAfterUpdate},
+		{"GetMoreWithStatus", testGetMoreWithStatus},
+		{"GetMoreByTagWithStatus", testGetMoreByTagWithStatus},
+		{"GetMoreAfterStatusUpdate", testGetMoreAfterStatusUpdate},
+		{"GetDue", testGetDue},
+		{"GetDueAfterPublishing", testGetDueAfterPublishing},
+		{"GetDeletedAfterUpdates", testGetDeletedAfterUpdates},
+		{"GetRevisionsAfterUpdates", testGetRevisionsAfterUpdates},
+		{"GetRevisionsAfterFailedUpdate", testGetRevisionsAfterFailedUpdate},
+		{"GetRevisionWithMissingRevision", testGetRevisionWithMissingRevision},
+		{"GetRevisionsAfterDelete", testGetRevisionsAfterDelete},
+	}
+
+	for _, test := range tests {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			test.test(t, factory())
+		})
+	}
+}
+
+// testCreateWithSuccess tests that a created blog post can be retrieved as it was stored.
+func testCreateWithSuccess(t *testing.T, repo generic.Repo) {
+	post := newPost("id", 1)
+
+	createdPost, err := repo.Create(post)
+	if err != nil {
+		t.Fatal("The creation was expected to succeed, but it failed with ", err)
+	}
+	if !createdPost.Equal(post) {
+		t.Error("The created post was expected to be ", post, " but it was ", createdPost)
+	}
+
+	storedPost, found, err := repo.Get(post.ID)
+	if err != nil || !found {
+		t.Fatal("The post was expected to be found, but it wasn't: ", err)
+	}
+	if !storedPost.Equal(post) {
+		t.Error("The stored post was expected to be ", post, " but it was ", storedPost)
+	}
+}
+
+// testGetKeyAfterUpdate tests that the internal key of a blog post is stored and kept when the blog post is updated.
+func testGetKeyAfterUpdate(t *testing.T, repo generic.Repo) {
+	post := newPost("id", 1)
+	post.Key = "key"
+	mustCreate(t, repo, post)
+
+	storedPost, _, err := repo.Get(post.ID)
+	if err != nil || storedPost.Key != post.Key {
+		t.Errorf("The key was expected to be %s, but it was %s (%v).", post.Key, storedPost.Key, err)
+	}
+
+	post.Title = "new title"
+	post.Revision = 2
+	if _, err := repo.Update(1, post); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+
+	storedPost, _, err = repo.Get(post.ID)
+	if err != nil || storedPost.Key != post.Key {
+		t.Errorf("The key was expected to be %s, but it was %s (%v).", post.Key, storedPost.Key, err)
+	}
+}
+
+// testCreateWithExistingID tests that a blog post cannot be created twice and that the stored one is untouched.
+func testCreateWithExistingID(t *testing.T, repo generic.Repo) {
+	post := newPost("id", 1)
+	mustCreate(t, repo, post)
+
+	otherPost := newPost("id", 2)
+	otherPost.Title = "other title"
+	_, err := repo.Create(otherPost)
+	if !errors.Is(err, generic.ErrAlreadyExists) {
+		t.Error("The error was expected to be ErrAlreadyExists, but it was ", err)
+	}
+
+	storedPost, _, _ := repo.Get(post.ID)
+	if !storedPost.Equal(post) {
+		t.Error("The stored post was expected to be ", post, " but it was ", storedPost)
+	}
+}
+
+// testUpdateWithSuccess tests that an update stores the new values and keeps the creation timestamp.
+func testUpdateWithSuccess(t *testing.T, repo generic.Repo) {
+	post := newPost("id", 1)
+	mustCreate(t, repo, post)
+
+	postUpdate := newPost("id", 2)
+	postUpdate.Title = "new title"
+	postUpdate.Body = "new body"
+	postUpdate.CreationTimestamp = 0
+	postUpdate.UpdateTimestamp = 200
+
+	expectedPost := postUpdate
+	expectedPost.CreationTimestamp = post.CreationTimestamp
+
+	updatedPost, err := repo.Update(1, postUpdate)
+	if err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+	if !updatedPost.Equal(expectedPost) {
+		t.Error("The updated post was expected to be ", expectedPost, " but it was ", updatedPost)
+	}
+
+	storedPost, _, _ := repo.Get(post.ID)
+	if !storedPost.Equal(expectedPost) {
+		t.Error("The stored post was expected to be ", expectedPost, " but it was ", storedPost)
+	}
+}
+
+// testUpdateWithWrongRevision tests that an update fails when the stored revision is different.
+func testUpdateWithWrongRevision(t *testing.T, repo generic.Repo) {
+	post := newPost("id", 2)
+	mustCreate(t, repo, post)
+
+	postUpdate := newPost("id", 3)
+	postUpdate.Title = "new title"
+	_, err := repo.Update(1, postUpdate)
+	if !errors.Is(err, generic.ErrRevisionMismatch) {
+		t.Error("The error was expected to be ErrRevisionMismatch, but it was ", err)
+	}
+
+	storedPost, _, _ := repo.Get(post.ID)
+	if !storedPost.Equal(post) {
+		t.Error("The stored post was expected to be ", post, " but it was ", storedPost)
+	}
+}
+
+// testUpdateWithMissingPost tests that an update fails when the blog post doesn't exist.
+func testUpdateWithMissingPost(t *testing.T, repo generic.Repo) {
+	_, err := repo.Update(1, newPost("id", 2))
+	if !errors.Is(err, generic.ErrNotFound) {
+		t.Error("The error was expected to be ErrNotFound, but it was ", err)
+	}
+}
+
+// testGetWithMissingPost tests that Get reports that a missing blog post was not found, without an error.
+func testGetWithMissingPost(t *testing.T, repo generic.Repo) {
+	_, found, err := repo.Get("missing")
+	if err != nil {
+		t.Error("No error was expected, but there was ", err)
+	}
+	if found {
+		t.Error("The post was expected not to be found, but it was.")
+	}
+}
+
+// testDeleteWithExistingPost tests that Delete reports that the blog post existed and removes it.
+func testDeleteWithExistingPost(t *testing.T, repo generic.Repo) {
+	post := newPost("id", 1)
+	mustCreate(t, repo, post)
+
+	found, err := repo.Delete(post.Revision, post.ID)
+	if err != nil || !found {
+		t.Error("The deletion was expected to find the post, but it didn't: ", err)
+	}
+
+	if _, found, _ = repo.Get(post.ID); found {
+		t.Error("The post was expected to be deleted, but it was found.")
+	}
+}
+
+// testDeleteWithMissingPost tests that Delete reports that a missing blog post was not found, without an error.
+func testDeleteWithMissingPost(t *testing.T, repo generic.Repo) {
+	found, err := repo.Delete(1, "missing")
+	if err != nil {
+		t.Error("No error was expected, but there was ", err)
+	}
+	if found {
+		t.Error("The post was expected not to be found, but it was.")
+	}
+}
+
+// testDeleteWithWrongRevision tests that a deletion fails when the stored revision is different.
+func testDeleteWithWrongRevision(t *testing.T, repo generic.Repo) {
+	post := newPost("id", 2)
+	mustCreate(t, repo, post)
+
+	_, err := repo.Delete(1, post.ID)
+	if !errors.Is(err, generic.ErrRevisionMismatch) {
+		t.Error("The error was expected to be ErrRevisionMismatch, but it was ", err)
+	}
+
+	storedPost, found, _ := repo.Get(post.ID)
+	if !found || !storedPost.Equal(post) {
+		t.Error("The stored post was expected to be ", post, " but it was ", storedPost)
+	}
+}
+
+// testGetAllWithNoPosts tests that GetAll returns an empty, non-nil slice and no cursor when there are no blog posts.
+func testGetAllWithNoPosts(t *testing.T, repo generic.Repo) {
+	posts, cursor, err := repo.GetAll(model.StatusPublished, generic.NewestFirst, 10)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if posts == nil || len(posts) != 0 {
+		t.Error("The posts were expected to be an empty slice, but they were ", posts)
+	}
+	if cursor != "" {
+		t.Error("The cursor was expected to be empty, but it was ", cursor)
+	}
+}
+
+// testGetAllWithFewPosts tests that GetAll returns every blog post and no cursor when they fit in a page.
+func testGetAllWithFewPosts(t *testing.T, repo generic.Repo) {
+	createPosts(t, repo, 3)
+
+	posts, cursor, err := repo.GetAll(model.StatusPublished, generic.NewestFirst, 10)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if len(posts) != 3 {
+		t.Errorf("The number of posts was expected to be 3, but it was %d.", len(posts))
+	}
+	if cursor != "" {
+		t.Error("The cursor was expected to be empty, but it was ", cursor)
+	}
+}
+
+// testGetMoreWithPaging tests that GetAll and GetMore walk through every blog post exactly once, sorted by their
+// creation timestamp in both orders.
+func testGetMoreWithPaging(t *testing.T, repo generic.Repo) {
+	ids := createPosts(t, repo, 7)
+
+	if oldestFirst := walk(t, allPosts(repo, generic.OldestFirst), 3); fmt.Sprint(oldestFirst) != fmt.Sprint(ids) {
+		t.Error("The posts were expected to be ", ids, " but they were ", oldestFirst)
+	}
+	newestFirst := walk(t, allPosts(repo, generic.NewestFirst), 3)
+	if fmt.Sprint(newestFirst) != fmt.Sprint(reversed(ids)) {
+		t.Error("The posts were expected to be ", reversed(ids), " but they were ", newestFirst)
+	}
+}
+
+// testGetAllAfterUpdate tests that an update doesn't change the position of a blog post.
+func testGetAllAfterUpdate(t *testing.T, repo generic.Repo) {
+	ids := createPosts(t, repo, 3)
+
+	postUpdate := newPost(ids[0], 2)
+	postUpdate.CreationTimestamp = 0
+	postUpdate.UpdateTimestamp = 1000
+	if _, err := repo.Update(1, postUpdate); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+
+	if oldestFirst := walk(t, allPosts(repo, generic.OldestFirst), 10); fmt.Sprint(oldestFirst) != fmt.Sprint(ids) {
+		t.Error("The posts were expected to be ", ids, " but they were ", oldestFirst)
+	}
+}
+
+// testGetMoreAfterLastPost tests that a page that ends with the last blog post has no cursor, even if it's full.
+func testGetMoreAfterLastPost(t *testing.T, repo generic.Repo) {
+	createPosts(t, repo, 4)
+
+	posts, cursor, err := repo.GetAll(model.StatusPublished, generic.NewestFirst, 2)
+	if err != nil || len(posts) != 2 || cursor == "" {
+		t.Fatalf("The first page was expected to have 2 posts and a cursor, but it had %d posts and cursor %q (%v).",
+			len(posts), cursor, err)
+	}
+
+	posts, cursor, err = repo.GetMore(model.StatusPublished, cursor, generic.NewestFirst, 2)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("The number of posts was expected to be 2, but it was %d.", len(posts))
+	}
+	if cursor != "" {
+		t.Error("The cursor was expected to be empty, but it was ", cursor)
+	}
+}
+
+// testGetMoreByTagAfterLastPost tests that a full page of a tag that ends with the last blog post with a status has
+// no cursor, even if there are more blog posts with the tag and other statuses.
+func testGetMoreByTagAfterLastPost(t *testing.T, repo generic.Repo) {
+	createPostsWith(t, repo, 4, func(i int, post *model.BlogPost) {
+		if i < 2 {
+			post.Status = model.StatusDraft
+		}
+	})
+
+	posts, cursor, err := repo.GetAllByTag("tags", model.StatusPublished, generic.NewestFirst, 2)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if len(posts) != 2 {
+		t.Errorf("The number of posts was expected to be 2, but it was %d.", len(posts))
+	}
+	if cursor != "" {
+		t.Error("The cursor was expected to be empty, but it was ", cursor)
+	}
+}
+
+// testGetMoreWithInvalidCursor tests that GetMore rejects cursors that were not issued by the repository.
+func testGetMoreWithInvalidCursor(t *testing.T, repo generic.Repo) {
+	createPosts(t, repo, 3)
+
+	_, cursor, _ := repo.GetAll(model.StatusPublished, generic.NewestFirst, 1)
+	if cursor == "" {
+		t.Fatal("The cursor was expected to be set, but it was empty.")
+	}
+
+	for _, invalidCursor := range []string{"invalid", "id0", cursor + "x", "x" + cursor} {
+		_, _, err := repo.GetMore(model.StatusPublished, invalidCursor, generic.NewestFirst, 1)
+		if !errors.Is(err, generic.ErrInvalidCursor) {
+			t.Error("The error for ", invalidCursor, " was expected to be ErrInvalidCursor, but it was ", err)
+		}
+	}
+}
+
+// testGetMoreByCategoryWithPaging tests that GetAllByCategory and GetMoreByCategory walk through every blog post of a
+// category exactly once, sorted by their creation timestamp.
+func testGetMoreByCategoryWithPaging(t *testing.T, repo generic.Repo) {
+	ids := createPostsWith(t, repo, 9, func(i int, post *model.BlogPost) {
+		post.Category = fmt.Sprintf("category%d", i%2)
+	})
+	categoryIDs := []string{ids[0], ids[2], ids[4], ids[6], ids[8]}
+
+	oldestFirst := walk(t, postsOfCategory(repo, "category0", generic.OldestFirst), 2)
+	if fmt.Sprint(oldestFirst) != fmt.Sprint(categoryIDs) {
+		t.Error("The posts were expected to be ", categoryIDs, " but they were ", oldestFirst)
+	}
+	newestFirst := walk(t, postsOfCategory(repo, "category0", generic.NewestFirst), 2)
+	if fmt.Sprint(newestFirst) != fmt.Sprint(reversed(categoryIDs)) {
+		t.Error("The posts were expected to be ", reversed(categoryIDs), " but they were ", newestFirst)
+	}
+}
+
+// testGetMoreWithCursorOfOtherPartition tests that the cursors of a category, an author or a tag are rejected for
+// another one.
+func testGetMoreWithCursorOfOtherPartition(t *testing.T, repo generic.Repo) {
+	createPostsWith(t, repo, 4, func(i int, post *model.BlogPost) {
+		post.Category = fmt.Sprintf("category%d", i%2)
+		post.AuthorID = fmt.Sprintf("author%d", i%2)
+		post.Tags = model.Tags{fmt.Sprintf("tag%d", i%2)}
+	})
+
+	_, categoryCursor, _ := repo.GetAllByCategory("category0", model.StatusPublished, generic.NewestFirst, 1)
+	_, authorCursor, _ := repo.GetAllByAuthor("author0", model.StatusPublished, generic.NewestFirst, 1)
+	_, tagCursor, _ := repo.GetAllByTag("tag0", model.StatusPublished, generic.NewestFirst, 1)
+	if categoryCursor == "" || authorCursor == "" || tagCursor == "" {
+		t.Fatal("The cursors were expected to be set, but at least one was empty.")
+	}
+
+	_, _, err := repo.GetMoreByCategory("category1", model.StatusPublished, categoryCursor, generic.NewestFirst, 1)
+	if !errors.Is(err, generic.ErrInvalidCursor) {
+		t.Error("The error of the category was expected to be ErrInvalidCursor, but it was ", err)
+	}
+	_, _, err = repo.GetMoreByAuthor("author1", model.StatusPublished, authorCursor, generic.NewestFirst, 1)
+	if !errors.Is(err, generic.ErrInvalidCursor) {
+		t.Error("The error of the author was expected to be ErrInvalidCursor, but it was ", err)
+	}
+	_, _, err = repo.GetMoreByTag("tag1", model.StatusPublished, tagCursor, generic.NewestFirst, 1)
+	if !errors.Is(err, generic.ErrInvalidCursor) {
+		t.Error("The error of the tag was expected to be ErrInvalidCursor, but it was ", err)
+	}
+}
+
+// testGetAllByCategoryWithMissingCategory tests that GetAllByCategory returns no blog posts for an unused category.
+func testGetAllByCategoryWithMissingCategory(t *testing.T, repo generic.Repo) {
+	createPosts(t, repo, 3)
+
+	posts, cursor, err := repo.GetAllByCategory("missing", model.StatusPublished, generic.NewestFirst, 10)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if posts == nil || len(posts) != 0 {
+		t.Error("The posts were expected to be an empty slice, but they were ", posts)
+	}
+	if cursor != "" {
+		t.Error("The cursor was expected to be empty, but it was ", cursor)
+	}
+}
+
+// testGetMoreByCategoryAfterUpdate tests that a blog post moves to another category when it's updated.
+func testGetMoreByCategoryAfterUpdate(t *testing.T, repo generic.Repo) {
+	ids := createPosts(t, repo, 3)
+
+	postUpdate := newPost(ids[1], 2)
+	postUpdate.Category = "other"
+	if _, err := repo.Update(1, postUpdate); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+
+	expectedIDs := []string{ids[0], ids[2]}
+	categoryIDs := walk(t, postsOfCategory(repo, "category", generic.OldestFirst), 10)
+	if fmt.Sprint(categoryIDs) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", categoryIDs)
+	}
+
+	expectedIDs = []string{ids[1]}
+	otherIDs := walk(t, postsOfCategory(repo, "other", generic.OldestFirst), 10)
+	if fmt.Sprint(otherIDs) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", otherIDs)
+	}
+}
+
+// testGetMoreByAuthorWithPaging tests that GetAllByAuthor and GetMoreByAuthor walk through every blog post of an
+// author exactly once, sorted by their creation timestamp.
+func testGetMoreByAuthorWithPaging(t *testing.T, repo generic.Repo) {
+	ids := createPostsWith(t, repo, 7, func(i int, post *model.BlogPost) {
+		post.AuthorID = fmt.Sprintf("author%d", i%3)
+	})
+	authorIDs := []string{ids[0], ids[3], ids[6]}
+
+	oldestFirst := walk(t, postsOfAuthor(repo, "author0", generic.OldestFirst), 2)
+	if fmt.Sprint(oldestFirst) != fmt.Sprint(authorIDs) {
+		t.Error("The posts were expected to be ", authorIDs, " but they were ", oldestFirst)
+	}
+	newestFirst := walk(t, postsOfAuthor(repo, "author0", generic.NewestFirst), 2)
+	if fmt.Sprint(newestFirst) != fmt.Sprint(reversed(authorIDs)) {
+		t.Error("The posts were expected to be ", reversed(authorIDs), " but they were ", newestFirst)
+	}
+}
+
+// testGetMoreByTagWithPaging tests that GetAllByTag and GetMoreByTag walk through every blog post with a tag exactly
+// once, sorted by their creation timestamp.
+func testGetMoreByTagWithPaging(t *testing.T, repo generic.Repo) {
+	ids := createPostsWith(t, repo, 9, func(i int, post *model.BlogPost) {
+		post.Tags = model.Tags{"all", fmt.Sprintf("tag%d", i%3)}
+	})
+	tagIDs := []string{ids[1], ids[4], ids[7]}
+
+	oldestFirst := walk(t, postsWithTag(repo, "tag1", model.StatusPublished, generic.OldestFirst), 2)
+	if fmt.Sprint(oldestFirst) != fmt.Sprint(tagIDs) {
+		t.Error("The posts were expected to be ", tagIDs, " but they were ", oldestFirst)
+	}
+	newestFirst := walk(t, postsWithTag(repo, "tag1", model.StatusPublished, generic.NewestFirst), 2)
+	if fmt.Sprint(newestFirst) != fmt.Sprint(reversed(tagIDs)) {
+		t.Error("The posts were expected to be ", reversed(tagIDs), " but they were ", newestFirst)
+	}
+	allIDs := walk(t, postsWithTag(repo, "all", model.StatusPublished, generic.OldestFirst), 4)
+	if fmt.Sprint(allIDs) != fmt.Sprint(ids) {
+		t.Error("The posts were expected to be ", ids, " but they were ", allIDs)
+	}
+}
+
+// testGetMoreByTagAfterUpdate tests that the tags of a blog post follow its updates.
+func testGetMoreByTagAfterUpdate(t *testing.T, repo generic.Repo) {
+	ids := createPostsWith(t, repo, 3, func(i int, post *model.BlogPost) {
+		post.Tags = model.Tags{"old", "kept"}
+	})
+
+	postUpdate := newPost(ids[1], 2)
+	postUpdate.Tags = model.Tags{"kept", "new"}
+	if _, err := repo.Update(1, postUpdate); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+
+	expectedIDs := map[string][]string{
+		"old":  {ids[0], ids[2]},
+		"kept": ids,
+		"new":  {ids[1]},
+	}
+	for tag, expected := range expectedIDs {
+		tagIDs := walk(t, postsWithTag(repo, tag, model.StatusPublished, generic.OldestFirst), 10)
+		if fmt.Sprint(tagIDs) != fmt.Sprint(expected) {
+			t.Error("The posts of ", tag, " were expected to be ", expected, " but they were ", tagIDs)
+		}
+	}
+}
+
+// testGetAllByTagAfterDelete tests that a deleted blog post is no longer listed under its tags.
+func testGetAllByTagAfterDelete(t *testing.T, repo generic.Repo) {
+	ids := createPosts(t, repo, 3)
+	if _, err := repo.Delete(1, ids[1]); err != nil {
+		t.Fatal("The deletion was expected to succeed, but it failed with ", err)
+	}
+
+	expectedIDs := []string{ids[0], ids[2]}
+	tagIDs := walk(t, postsWithTag(repo, "tags", model.StatusPublished, generic.OldestFirst), 10)
+	if fmt.Sprint(tagIDs) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", tagIDs)
+	}
+}
+
+// testGetTagCounts tests that GetTagCounts counts the blog posts of every tag and sorts the tags.
+func testGetTagCounts(t *testing.T, repo generic.Repo) {
+	counts, err := repo.GetTagCounts(model.StatusPublished)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if counts == nil || len(counts) != 0 {
+		t.Error("The tag counts were expected to be an empty slice, but they were ", counts)
+	}
+
+	ids := createPostsWith(t, repo, 4, func(i int, post *model.BlogPost) {
+		post.Tags = model.Tags{"go", fmt.Sprintf("tag%d", i%2)}
+	})
+	if _, err = repo.Delete(1, ids[3]); err != nil {
+		t.Fatal("The deletion was expected to succeed, but it failed with ", err)
+	}
+
+	expectedCounts := []model.TagCount{{Tag: "go", Count: 3}, {Tag: "tag0", Count: 2}, {Tag: "tag1", Count: 1}}
+	counts, err = repo.GetTagCounts(model.StatusPublished)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if fmt.Sprint(counts) != fmt.Sprint(expectedCounts) {
+		t.Error("The tag counts were expected to be ", expectedCounts, " but they were ", counts)
+	}
+}
+
+// testGetTagCountsAfterUpdate tests that the tag counts follow the tags and the status of an updated blog post.
+func testGetTagCountsAfterUpdate(t *testing.T, repo generic.Repo) {
+	ids := createPostsWith(t, repo, 2, func(i int, post *model.BlogPost) {
+		post.Tags = model.Tags{"go", "aws"}
+	})
+
+	postUpdate := newPost(ids[0], 2)
+	postUpdate.Tags = model.Tags{"go", "lambda"}
+	postUpdate.Status = model.StatusDraft
+	if _, err := repo.Update(1, postUpdate); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+
+	expectedCounts := map[model.Status][]model.TagCount{
+		model.StatusPublished: {{Tag: "aws", Count: 1}, {Tag: "go", Count: 1}},
+		model.StatusDraft:     {{Tag: "go", Count: 1}, {Tag: "lambda", Count: 1}},
+	}
+	for status, expected := range expectedCounts {
+		counts, err := repo.GetTagCounts(status)
+		if err != nil {
+			t.Fatal("No error was expected, but there was ", err)
+		}
+		if fmt.Sprint(counts) != fmt.Sprint(expected) {
+			t.Error("The tag counts of ", status, " were expected to be ", expected, " but they were ", counts)
+		}
+	}
+}
+
+// testGetMoreWithStatus tests that GetAll and GetMore only list the blog posts with the requested status and that
+// blog posts without a status are listed as published.
+func testGetMoreWithStatus(t *testing.T, repo generic.Repo) {
+	statuses := []model.Status{model.StatusDraft, model.StatusPublished, model.StatusArchived, ""}
+	ids := createPostsWith(t, repo, 8, func(i int, post *model.BlogPost) {
+		post.Status = statuses[i%len(statuses)]
+	})
+
+	expectedIDs := map[model.Status][]string{
+		model.StatusDraft:     {ids[0], ids[4]},
+		model.StatusPublished: {ids[1], ids[3], ids[5], ids[7]},
+		model.StatusArchived:  {ids[2], ids[6]},
+	}
+	for status, expected := range expectedIDs {
+		statusIDs := walk(t, postsWithStatus(repo, status, generic.OldestFirst), 1)
+		if fmt.Sprint(statusIDs) != fmt.Sprint(expected) {
+			t.Error("The posts with status ", status, " were expected to be ", expected, " but they were ", statusIDs)
+		}
+	}
+}
+
+// testGetMoreByTagWithStatus tests that the blog posts of a tag and the tag counts only include the blog posts with
+// the requested status.
+func testGetMoreByTagWithStatus(t *testing.T, repo generic.Repo) {
+	ids := createPostsWith(t, repo, 4, func(i int, post *model.BlogPost) {
+		post.Tags = model.Tags{"tag"}
+		if i%2 == 0 {
+			post.Status = model.StatusDraft
+		}
+	})
+
+	expectedIDs := []string{ids[0], ids[2]}
+	draftIDs := walk(t, postsWithTag(repo, "tag", model.StatusDraft, generic.OldestFirst), 1)
+	if fmt.Sprint(draftIDs) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", draftIDs)
+	}
+
+	expectedCounts := []model.TagCount{{Tag: "tag", Count: 2}}
+	counts, err := repo.GetTagCounts(model.StatusDraft)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if fmt.Sprint(counts) != fmt.Sprint(expectedCounts) {
+		t.Error("The tag counts were expected to be ", expectedCounts, " but they were ", counts)
+	}
+}
+
+// testGetMoreAfterStatusUpdate tests that a blog post moves to the listing of its new status when it's updated.
+func testGetMoreAfterStatusUpdate(t *testing.T, repo generic.Repo) {
+	ids := createPostsWith(t, repo, 3, func(i int, post *model.BlogPost) {
+		post.Status = model.StatusDraft
+	})
+
+	postUpdate := newPost(ids[1], 2)
+	postUpdate.Status = model.StatusPublished
+	postUpdate.PublishedTimestamp = 200
+	updatedPost, err := repo.Update(1, postUpdate)
+	if err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+	if updatedPost.Status != model.StatusPublished || updatedPost.PublishedTimestamp != 200 {
+		t.Error("The updated post was expected to be published at 200, but it was ", updatedPost)
+	}
+
+	expectedIDs := []string{ids[0], ids[2]}
+	draftIDs := walk(t, postsWithStatus(repo, model.StatusDraft, generic.OldestFirst), 10)
+	if fmt.Sprint(draftIDs) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", draftIDs)
+	}
+
+	expectedIDs = []string{ids[1]}
+	publishedIDs := walk(t, allPosts(repo, generic.OldestFirst), 10)
+	if fmt.Sprint(publishedIDs) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", publishedIDs)
+	}
+	tagIDs := walk(t, postsWithTag(repo, "tags", model.StatusPublished, generic.OldestFirst), 10)
+	if fmt.Sprint(tagIDs) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", tagIDs)
+	}
+}
+
+// testGetDue tests that GetDue only returns the scheduled drafts that are due, earliest first.
+func testGetDue(t *testing.T, repo generic.Repo) {
+	schedules := []struct {
+		status      model.Status
+		scheduledAt int64
+	}{
+		{model.StatusDraft, 150},
+		{model.StatusDraft, 120},
+		{model.StatusDraft, 300},
+		{model.StatusDraft, 0},
+		{model.StatusPublished, 110},
+		{model.StatusDraft, 200},
+	}
+	ids := createPostsWith(t, repo, len(schedules), func(i int, post *model.BlogPost) {
+		post.Status = schedules[i].status
+		post.ScheduledAt = schedules[i].scheduledAt
+	})
+
+	expectedIDs := []string{ids[1], ids[0], ids[5]}
+	posts, err := repo.GetDue(200, 10)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if fmt.Sprint(postIDs(posts)) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", postIDs(posts))
+	}
+
+	expectedIDs = expectedIDs[:2]
+	posts, err = repo.GetDue(200, 2)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if fmt.Sprint(postIDs(posts)) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", postIDs(posts))
+	}
+}
+
+// testGetDueAfterPublishing tests that a scheduled draft is no longer due once it's published.
+func testGetDueAfterPublishing(t *testing.T, repo generic.Repo) {
+	ids := createPostsWith(t, repo, 2, func(i int, post *model.BlogPost) {
+		post.Status = model.StatusDraft
+		post.ScheduledAt = 150
+	})
+
+	postUpdate := newPost(ids[0], 2)
+	postUpdate.Status = model.StatusPublished
+	if _, err := repo.Update(1, postUpdate); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+
+	expectedIDs := []string{ids[1]}
+	posts, err := repo.GetDue(200, 10)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if fmt.Sprint(postIDs(posts)) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", postIDs(posts))
+	}
+}
+
+// testGetDeletedAfterUpdates tests that GetDeleted only returns the blog posts that were moved to the trash at or
+// before the timestamp, earliest first, and that they are no longer listed with their old status.
+func testGetDeletedAfterUpdates(t *testing.T, repo generic.Repo) {
+	ids := createPosts(t, repo, 4)
+	for i, deletedTimestamp := range []int64{150, 120, 300} {
+		postUpdate := newPost(ids[i], 2)
+		postUpdate.Status = model.StatusDeleted
+		postUpdate.DeletedTimestamp = deletedTimestamp
+		if _, err := repo.Update(1, postUpdate); err != nil {
+			t.Fatal("The update was expected to succeed, but it failed with ", err)
+		}
+	}
+
+	expectedIDs := []string{ids[1], ids[0]}
+	posts, err := repo.GetDeleted(200, 10)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if fmt.Sprint(postIDs(posts)) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", postIDs(posts))
+	}
+
+	expectedIDs = []string{ids[3]}
+	if ids := walk(t, allPosts(repo, generic.NewestFirst), 10); fmt.Sprint(ids) != fmt.Sprint(expectedIDs) {
+		t.Error("The posts were expected to be ", expectedIDs, " but they were ", ids)
+	}
+}
+
+// testGetRevisionsAfterUpdates tests that every update keeps a snapshot of the revision it replaced.
+func testGetRevisionsAfterUpdates(t *testing.T, repo generic.Repo) {
+	post := newPost("id", 1)
+	post.Tags = model.Tags{"first", "second"}
+	mustCreate(t, repo, post)
+
+	secondPost := newPost("id", 2)
+	secondPost.Title = "second title"
+	if _, err := repo.Update(1, secondPost); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+	thirdPost := newPost("id", 3)
+	thirdPost.Title = "third title"
+	if _, err := repo.Update(2, thirdPost); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+
+	revisions, err := repo.GetRevisions(post.ID)
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if len(revisions) != 2 || !revisions[0].Equal(secondPost) || !revisions[1].Equal(post) {
+		t.Error("The revisions were expected to be ", []model.BlogPost{secondPost, post}, " but they were ",
+			revisions)
+	}
+
+	revision, found, err := repo.GetRevision(post.ID, 1)
+	if err != nil || !found {
+		t.Fatal("The revision was expected to be found, but it wasn't: ", err)
+	}
+	if !revision.Equal(post) {
+		t.Error("The revision was expected to be ", post, " but it was ", revision)
+	}
+}
+
+// testGetRevisionsAfterFailedUpdate tests that a failed update doesn't keep a snapshot.
+func testGetRevisionsAfterFailedUpdate(t *testing.T, repo generic.Repo) {
+	mustCreate(t, repo, newPost("id", 2))
+
+	if _, err := repo.Update(1, newPost("id", 3)); !errors.Is(err, generic.ErrRevisionMismatch) {
+		t.Error("The error was expected to be ErrRevisionMismatch, but it was ", err)
+	}
+
+	revisions, err := repo.GetRevisions("id")
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if revisions == nil || len(revisions) != 0 {
+		t.Error("The revisions were expected to be empty, but they were ", revisions)
+	}
+}
+
+// testGetRevisionWithMissingRevision tests that GetRevision reports that a missing revision was not found, without
+// an error. The current revision of a blog post is not a snapshot.
+func testGetRevisionWithMissingRevision(t *testing.T, repo generic.Repo) {
+	mustCreate(t, repo, newPost("id", 1))
+
+	for _, revision := range []int64{1, 2} {
+		_, found, err := repo.GetRevision("id", revision)
+		if err != nil {
+			t.Error("No error was expected, but there was ", err)
+		}
+		if found {
+			t.Errorf("The revision %d was expected not to be found, but it was.", revision)
+		}
+	}
+}
+
+// testGetRevisionsAfterDelete tests that deleting a blog post removes its revisions too.
+func testGetRevisionsAfterDelete(t *testing.T, repo generic.Repo) {
+	mustCreate(t, repo, newPost("id", 1))
+	if _, err := repo.Update(1, newPost("id", 2)); err != nil {
+		t.Fatal("The update was expected to succeed, but it failed with ", err)
+	}
+	if _, err := repo.Delete(2, "id"); err != nil {
+		t.Fatal("The deletion was expected to succeed, but it failed with ", err)
+	}
+
+	revisions, err := repo.GetRevisions("id")
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if len(revisions) != 0 {
+		t.Error("The revisions were expected to be empty, but they were ", revisions)
+	}
+}
+
+// pager loads a page of blog posts. An empty cursor loads the first page.
+type pager func(cursor string, pageSize int64) ([]model.BlogPost, string, error)
+
+// allPosts returns a pager that lists all published blog posts.
+func allPosts(repo generic.Repo, order generic.SortOrder) pager {
+	return postsWithStatus(repo, model.StatusPublished, order)
+}
+
+// postsWithStatus returns a pager that lists all blog posts with a status.
+func postsWithStatus(repo generic.Repo, status model.Status, order generic.SortOrder) pager {
+	return func(cursor string, pageSize int64) ([]model.BlogPost, string, error) {
+		if cursor == "" {
+			return repo.GetAll(status, order, pageSize)
+		}
+		return repo.GetMore(status, cursor, order, pageSize)
+	}
+}
+
+// postsOfCategory returns a pager that lists the published blog posts of a category.
+func postsOfCategory(repo generic.Repo, category string, order generic.SortOrder) pager {
+	return func(cursor string, pageSize int64) ([]model.BlogPost, string, error) {
+		if cursor == "" {
+			return repo.GetAllByCategory(category, model.StatusPublished, order, pageSize)
+		}
+		return repo.GetMoreByCategory(category, model.StatusPublished, cursor, order, pageSize)
+	}
+}
+
+// postsOfAuthor returns a pager that lists the published blog posts of an author.
+func postsOfAuthor(repo generic.Repo, authorID string, order generic.SortOrder) pager {
+	return func(cursor string, pageSize int64) ([]model.BlogPost, string, error) {
+		if cursor == "" {
+			return repo.GetAllByAuthor(authorID, model.StatusPublished, order, pageSize)
+		}
+		return repo.GetMoreByAuthor(authorID, model.StatusPublished, cursor, order, pageSize)
+	}
+}
+
+// postsWithTag returns a pager that lists the blog posts with a tag and a status.
+func postsWithTag(repo generic.Repo, tag string, status model.Status, order generic.SortOrder) pager {
+	return func(cursor string, pageSize int64) ([]model.BlogPost, string, error) {
+		if cursor == "" {
+			return repo.GetAllByTag(tag, status, order, pageSize)
+		}
+		return repo.GetMoreByTag(tag, status, cursor, order, pageSize)
+	}
+}
+
+// walk loads every blog post page by page and returns their ids in the order they were returned.
+func walk(t *testing.T, load pager, pageSize int64) []string {
+	posts, cursor, err := load("", pageSize)
+	ids := []string{}
+
+	for err == nil {
+		if int64(len(posts)) > pageSize {
+			t.Fatalf("The page was expected to have up to %d posts, but it had %d.", pageSize, len(posts))
+		}
+		for _, post := range posts {
+			ids = append(ids, post.ID)
+		}
+		if cursor == "" {
+			break
+		}
+
+		posts, cursor, err = load(cursor, pageSize)
+	}
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+
+	return ids
+}
+
+func newPost(id string, revision int64) model.BlogPost {
+	return model.BlogPost{ID: id, Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: revision, Status: model.StatusPublished,
+		PublishedTimestamp: 100, CreationTimestamp: 100, UpdateTimestamp: 100}
+}
+
+func mustCreate(t *testing.T, repo generic.Repo, post model.BlogPost) {
+	if _, err := repo.Create(post); err != nil {
+		t.Fatal("The creation was expected to succeed, but it failed with ", err)
+	}
+}
+
+// createPosts creates blog posts with increasing creation timestamps and returns their ids, oldest first.
+func createPosts(t *testing.T, repo generic.Repo, count int) []string {
+	return createPostsWith(t, repo, count, func(int, *model.BlogPost) {})
+}
+
+// createPostsWith creates blog posts like createPosts, but lets the caller change each post before it's created.
+func createPostsWith(t *testing.T, repo generic.Repo, count int, change func(int, *model.BlogPost)) []string {
+	ids := make([]string, count)
+	for i := 0; i < count; i++ {
+		ids[i] = fmt.Sprintf("id%d", count-i)
+		post := newPost(ids[i], 1)
+		post.CreationTimestamp = int64(100 + i)
+		change(i, &post)
+		mustCreate(t, repo, post)
+	}
+
+	return ids
+}
+
+// postIDs returns the ids of the blog posts, in the same order.
+func postIDs(posts []model.BlogPost) []string {
+	ids := make([]string, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+
+	return ids
+}
+
+// reversed returns a reversed copy of the ids.
+func reversed(ids []string) []string {
+	result := make([]string, len(ids))
+	for i, id := range ids {
+		result[len(ids)-1-i] = id
+	}
+
+	return result
+}
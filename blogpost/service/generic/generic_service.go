@@ -2,15 +2,40 @@ package generic
 
 import (
 	"github.com/printezisn/serverless-blog-back/blogpost/model"
-	gloBalModel "github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
 )
 
-// Service represents the service layer for blog posts.
+// Service represents the service layer for blog posts. The operations that change blog posts or read the ones that are
+// not published take the identity of the caller, so that they can check its permissions.
 type Service interface {
-	Create(post model.BlogPost) gloBalModel.Response
-	Update(post model.BlogPost) gloBalModel.Response
-	Delete(id string) gloBalModel.Response
-	Get(id string) gloBalModel.Response
-	GetAll() gloBalModel.Response
-	GetMore(lastID string) gloBalModel.Response
+	Create(caller auth.Identity, post model.BlogPost) globalModel.Response
+	Update(caller auth.Identity, post model.BlogPost) globalModel.Response
+	Patch(caller auth.Identity, id string, revision int64, patch model.Patch) globalModel.Response
+	Delete(caller auth.Identity, id string, revision int64) globalModel.Response
+	Restore(caller auth.Identity, id string) globalModel.Response
+	Get(id string) globalModel.Response
+	GetDraft(caller auth.Identity, id string) globalModel.Response
+	GetAll(pageSize int64) globalModel.Response
+	GetMore(cursor string, pageSize int64) globalModel.Response
+	GetAllByCategory(category string, pageSize int64) globalModel.Response
+	GetMoreByCategory(category string, cursor string, pageSize int64) globalModel.Response
+	GetAllByAuthor(authorID string, pageSize int64) globalModel.Response
+	GetMoreByAuthor(authorID string, cursor string, pageSize int64) globalModel.Response
+	GetAllByTag(tag string, pageSize int64) globalModel.Response
+	GetMoreByTag(tag string, cursor string, pageSize int64) globalModel.Response
+	GetTags() globalModel.Response
+	GetAllDrafts(caller auth.Identity, pageSize int64) globalModel.Response
+	GetMoreDrafts(caller auth.Identity, cursor string, pageSize int64) globalModel.Response
+	GetAllTrash(caller auth.Identity, pageSize int64) globalModel.Response
+	GetMoreTrash(caller auth.Identity, cursor string, pageSize int64) globalModel.Response
+	PublishScheduled() globalModel.Response
+	PurgeTrash() globalModel.Response
+	GetRevisions(id string) globalModel.Response
+	GetRevision(id string, revision int64) globalModel.Response
+	GetDraftRevisions(caller auth.Identity, id string) globalModel.Response
+	GetDraftRevision(caller auth.Identity, id string, revision int64) globalModel.Response
+	RestoreRevision(caller auth.Identity, id string, revision int64) globalModel.Response
+	GetDiff(id string, from int64, to int64) globalModel.Response
+	GetDraftDiff(caller auth.Identity, id string, from int64, to int64) globalModel.Response
 }
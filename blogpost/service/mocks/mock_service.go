@@ -2,6 +2,7 @@
 
 package mocks
 
+import auth "github.com/printezisn/serverless-blog-back/global/auth"
 import globalmodel "github.com/printezisn/serverless-blog-back/global/model"
 import mock "github.com/stretchr/testify/mock"
 import model "github.com/printezisn/serverless-blog-back/blogpost/model"
@@ -11,13 +12,13 @@ type Service struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: post
-func (_m *Service) Create(post model.BlogPost) globalmodel.Response {
-	ret := _m.Called(post)
+// Create provides a mock function with given fields: caller, post
+func (_m *Service) Create(caller auth.Identity, post model.BlogPost) globalmodel.Response {
+	ret := _m.Called(caller, post)
 
 	var r0 globalmodel.Response
-	if rf, ok := ret.Get(0).(func(model.BlogPost) globalmodel.Response); ok {
-		r0 = rf(post)
+	if rf, ok := ret.Get(0).(func(auth.Identity, model.BlogPost) globalmodel.Response); ok {
+		r0 = rf(caller, post)
 	} else {
 		r0 = ret.Get(0).(globalmodel.Response)
 	}
@@ -25,8 +26,22 @@ func (_m *Service) Create(post model.BlogPost) globalmodel.Response {
 	return r0
 }
 
-// Delete provides a mock function with given fields: id
-func (_m *Service) Delete(id string) globalmodel.Response {
+// Delete provides a mock function with given fields: caller, id, revision
+func (_m *Service) Delete(caller auth.Identity, id string, revision int64) globalmodel.Response {
+	ret := _m.Called(caller, id, revision)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string, int64) globalmodel.Response); ok {
+		r0 = rf(caller, id, revision)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// Get provides a mock function with given fields: id
+func (_m *Service) Get(id string) globalmodel.Response {
 	ret := _m.Called(id)
 
 	var r0 globalmodel.Response
@@ -39,8 +54,260 @@ func (_m *Service) Delete(id string) globalmodel.Response {
 	return r0
 }
 
-// Get provides a mock function with given fields: id
-func (_m *Service) Get(id string) globalmodel.Response {
+// GetAll provides a mock function with given fields: pageSize
+func (_m *Service) GetAll(pageSize int64) globalmodel.Response {
+	ret := _m.Called(pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(int64) globalmodel.Response); ok {
+		r0 = rf(pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetAllByAuthor provides a mock function with given fields: authorID, pageSize
+func (_m *Service) GetAllByAuthor(authorID string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(authorID, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, int64) globalmodel.Response); ok {
+		r0 = rf(authorID, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetAllByCategory provides a mock function with given fields: category, pageSize
+func (_m *Service) GetAllByCategory(category string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(category, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, int64) globalmodel.Response); ok {
+		r0 = rf(category, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetAllByTag provides a mock function with given fields: tag, pageSize
+func (_m *Service) GetAllByTag(tag string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(tag, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, int64) globalmodel.Response); ok {
+		r0 = rf(tag, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetAllDrafts provides a mock function with given fields: caller, pageSize
+func (_m *Service) GetAllDrafts(caller auth.Identity, pageSize int64) globalmodel.Response {
+	ret := _m.Called(caller, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, int64) globalmodel.Response); ok {
+		r0 = rf(caller, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetAllTrash provides a mock function with given fields: caller, pageSize
+func (_m *Service) GetAllTrash(caller auth.Identity, pageSize int64) globalmodel.Response {
+	ret := _m.Called(caller, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, int64) globalmodel.Response); ok {
+		r0 = rf(caller, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetDiff provides a mock function with given fields: id, from, to
+func (_m *Service) GetDiff(id string, from int64, to int64) globalmodel.Response {
+	ret := _m.Called(id, from, to)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, int64, int64) globalmodel.Response); ok {
+		r0 = rf(id, from, to)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetDraft provides a mock function with given fields: caller, id
+func (_m *Service) GetDraft(caller auth.Identity, id string) globalmodel.Response {
+	ret := _m.Called(caller, id)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string) globalmodel.Response); ok {
+		r0 = rf(caller, id)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetDraftDiff provides a mock function with given fields: caller, id, from, to
+func (_m *Service) GetDraftDiff(caller auth.Identity, id string, from int64, to int64) globalmodel.Response {
+	ret := _m.Called(caller, id, from, to)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string, int64, int64) globalmodel.Response); ok {
+		r0 = rf(caller, id, from, to)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetDraftRevision provides a mock function with given fields: caller, id, revision
+func (_m *Service) GetDraftRevision(caller auth.Identity, id string, revision int64) globalmodel.Response {
+	ret := _m.Called(caller, id, revision)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string, int64) globalmodel.Response); ok {
+		r0 = rf(caller, id, revision)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetDraftRevisions provides a mock function with given fields: caller, id
+func (_m *Service) GetDraftRevisions(caller auth.Identity, id string) globalmodel.Response {
+	ret := _m.Called(caller, id)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string) globalmodel.Response); ok {
+		r0 = rf(caller, id)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetMore provides a mock function with given fields: cursor, pageSize
+func (_m *Service) GetMore(cursor string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(cursor, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, int64) globalmodel.Response); ok {
+		r0 = rf(cursor, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetMoreByAuthor provides a mock function with given fields: authorID, cursor, pageSize
+func (_m *Service) GetMoreByAuthor(authorID string, cursor string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(authorID, cursor, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, string, int64) globalmodel.Response); ok {
+		r0 = rf(authorID, cursor, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetMoreByCategory provides a mock function with given fields: category, cursor, pageSize
+func (_m *Service) GetMoreByCategory(category string, cursor string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(category, cursor, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, string, int64) globalmodel.Response); ok {
+		r0 = rf(category, cursor, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetMoreByTag provides a mock function with given fields: tag, cursor, pageSize
+func (_m *Service) GetMoreByTag(tag string, cursor string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(tag, cursor, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, string, int64) globalmodel.Response); ok {
+		r0 = rf(tag, cursor, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetMoreDrafts provides a mock function with given fields: caller, cursor, pageSize
+func (_m *Service) GetMoreDrafts(caller auth.Identity, cursor string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(caller, cursor, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string, int64) globalmodel.Response); ok {
+		r0 = rf(caller, cursor, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetMoreTrash provides a mock function with given fields: caller, cursor, pageSize
+func (_m *Service) GetMoreTrash(caller auth.Identity, cursor string, pageSize int64) globalmodel.Response {
+	ret := _m.Called(caller, cursor, pageSize)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string, int64) globalmodel.Response); ok {
+		r0 = rf(caller, cursor, pageSize)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetRevision provides a mock function with given fields: id, revision
+func (_m *Service) GetRevision(id string, revision int64) globalmodel.Response {
+	ret := _m.Called(id, revision)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(string, int64) globalmodel.Response); ok {
+		r0 = rf(id, revision)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// GetRevisions provides a mock function with given fields: id
+func (_m *Service) GetRevisions(id string) globalmodel.Response {
 	ret := _m.Called(id)
 
 	var r0 globalmodel.Response
@@ -53,8 +320,8 @@ func (_m *Service) Get(id string) globalmodel.Response {
 	return r0
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *Service) GetAll() globalmodel.Response {
+// GetTags provides a mock function with given fields:
+func (_m *Service) GetTags() globalmodel.Response {
 	ret := _m.Called()
 
 	var r0 globalmodel.Response
@@ -67,13 +334,69 @@ func (_m *Service) GetAll() globalmodel.Response {
 	return r0
 }
 
-// GetMore provides a mock function with given fields: lastID
-func (_m *Service) GetMore(lastID string) globalmodel.Response {
-	ret := _m.Called(lastID)
+// Patch provides a mock function with given fields: caller, id, revision, patch
+func (_m *Service) Patch(caller auth.Identity, id string, revision int64, patch model.Patch) globalmodel.Response {
+	ret := _m.Called(caller, id, revision, patch)
 
 	var r0 globalmodel.Response
-	if rf, ok := ret.Get(0).(func(string) globalmodel.Response); ok {
-		r0 = rf(lastID)
+	if rf, ok := ret.Get(0).(func(auth.Identity, string, int64, model.Patch) globalmodel.Response); ok {
+		r0 = rf(caller, id, revision, patch)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// PublishScheduled provides a mock function with given fields:
+func (_m *Service) PublishScheduled() globalmodel.Response {
+	ret := _m.Called()
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func() globalmodel.Response); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// PurgeTrash provides a mock function with given fields:
+func (_m *Service) PurgeTrash() globalmodel.Response {
+	ret := _m.Called()
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func() globalmodel.Response); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// Restore provides a mock function with given fields: caller, id
+func (_m *Service) Restore(caller auth.Identity, id string) globalmodel.Response {
+	ret := _m.Called(caller, id)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string) globalmodel.Response); ok {
+		r0 = rf(caller, id)
+	} else {
+		r0 = ret.Get(0).(globalmodel.Response)
+	}
+
+	return r0
+}
+
+// RestoreRevision provides a mock function with given fields: caller, id, revision
+func (_m *Service) RestoreRevision(caller auth.Identity, id string, revision int64) globalmodel.Response {
+	ret := _m.Called(caller, id, revision)
+
+	var r0 globalmodel.Response
+	if rf, ok := ret.Get(0).(func(auth.Identity, string, int64) globalmodel.Response); ok {
+		r0 = rf(caller, id, revision)
 	} else {
 		r0 = ret.Get(0).(globalmodel.Response)
 	}
@@ -81,13 +404,13 @@ func (_m *Service) GetMore(lastID string) globalmodel.Response {
 	return r0
 }
 
-// Update provides a mock function with given fields: post
-func (_m *Service) Update(post model.BlogPost) globalmodel.Response {
-	ret := _m.Called(post)
+// Update provides a mock function with given fields: caller, post
+func (_m *Service) Update(caller auth.Identity, post model.BlogPost) globalmodel.Response {
+	ret := _m.Called(caller, post)
 
 	var r0 globalmodel.Response
-	if rf, ok := ret.Get(0).(func(model.BlogPost) globalmodel.Response); ok {
-		r0 = rf(post)
+	if rf, ok := ret.Get(0).(func(auth.Identity, model.BlogPost) globalmodel.Response); ok {
+		r0 = rf(caller, post)
 	} else {
 		r0 = ret.Get(0).(globalmodel.Response)
 	}
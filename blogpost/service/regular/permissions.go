@@ -0,0 +1,42 @@
+package regular
+
+import (
+	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// canChange checks if a caller may change a blog post. Editors may change every blog post and authors only the ones
+// they have written.
+func canChange(caller auth.Identity, post model.BlogPost) bool {
+	if caller.HasRole(auth.RoleEditor) {
+		return true
+	}
+
+	return caller.HasRole(auth.RoleAuthor) && post.AuthorID != "" && post.AuthorID == caller.Subject
+}
+
+// canView checks if a caller may read a blog post that is not published, together with its revisions. Editors may read
+// every blog post and authors only the ones they have written, just like the ones they may change.
+func canView(caller auth.Identity, post model.BlogPost) bool {
+	return canChange(caller, post)
+}
+
+// canSetStatus checks if a caller may change the status or the scheduled time of a blog post. Only editors may
+// publish or archive blog posts, or schedule drafts to be published. Authors may still change blog posts that keep
+// their status and scheduled time.
+func canSetStatus(caller auth.Identity, currentPost model.BlogPost, post model.BlogPost) bool {
+	if caller.HasRole(auth.RoleEditor) {
+		return true
+	}
+	if post.CurrentStatus() != currentPost.CurrentStatus() && !post.HasStatus(model.StatusDraft) {
+		return false
+	}
+
+	return post.ScheduledAt == 0 || post.ScheduledAt == currentPost.ScheduledAt
+}
+
+// forbidden returns the response of an operation that the caller is not allowed to do.
+func forbidden(entity interface{}, message string) globalModel.Response {
+	return globalModel.Response{Entity: entity, Errors: []globalModel.Error{globalModel.NewError(globalModel.CodeForbidden, message)}, StatusCode: 403}
+}
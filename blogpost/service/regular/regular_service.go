@@ -1,72 +1,186 @@
 package regular
 
 import (
+	"errors"
+	"fmt"
 	"log"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 
-	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/printezisn/serverless-blog-back/blogpost/model"
 	postRepo "github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
-	gloBalModel "github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/ulid"
 )
 
+// maxSlugNumber is the highest number that is added to a generated slug to tell it apart from the ids of other blog
+// posts.
+const maxSlugNumber = 100
+
 // Service represents the regular service layer for blog posts.
 type Service struct {
-	repo     postRepo.Repo
-	pageSize int64
+	repo           postRepo.Repo
+	pageSize       int64
+	maxPageSize    int64
+	trashRetention time.Duration
+	validation     model.ValidationPolicy
 }
 
-// New creates a new instance of the regular service layer for blog posts.
+// New creates a new instance of the regular service layer for blog posts. Blog posts are kept in the trash for the
+// number of days in the TRASH_RETENTION_DAYS environment variable, or 30 days if it's not set, and are validated
+// against the policy of the environment (see model.NewValidationPolicy).
 func New(repo postRepo.Repo) Service {
-	return Service{repo: repo, pageSize: 10}
+	retentionDays, err := strconv.ParseInt(os.Getenv("TRASH_RETENTION_DAYS"), 10, 64)
+	if err != nil || retentionDays < 0 {
+		retentionDays = 30
+	}
+
+	return Service{repo: repo, pageSize: 10, maxPageSize: 100,
+		trashRetention: time.Duration(retentionDays) * 24 * time.Hour, validation: model.NewValidationPolicy()}
 }
 
-// Create creates a new blog post.
-func (service *Service) Create(post model.BlogPost) gloBalModel.Response {
-	errs := post.Validate()
+// Create creates a new blog post, written by the caller. A blog post without a status is published, or a draft if the
+// caller may not publish it, and a new blog post cannot be archived. Only drafts keep their scheduled time. A blog post
+// without an id gets the slug of its title, with a number at the end if another blog post has the same one or if it's
+// reserved, and ids that are given must match the id pattern of the validation policy, which are slugs by default.
+// Every new blog post also gets a ULID as its key, which sorts by the creation time.
+func (service *Service) Create(caller auth.Identity, post model.BlogPost) globalModel.Response {
+	if !caller.HasRole(auth.RoleAuthor) {
+		return forbidden(post, "Only authors, editors and admins may create blog posts.")
+	}
+
+	post.Tags = model.NormalizeTags(post.Tags)
+	if post.Status == "" && !caller.HasRole(auth.RoleEditor) {
+		post.Status = model.StatusDraft
+	}
+	post.Status = post.CurrentStatus()
+	post.AuthorID = caller.Subject
+	post.Key = ulid.New()
+	if !canSetStatus(caller, model.BlogPost{Status: model.StatusDraft}, post) {
+		return forbidden(post, "Only editors and admins may publish or schedule blog posts.")
+	}
+	if post.Status != model.StatusDraft {
+		post.ScheduledAt = 0
+	}
+
+	slug, generated, number := post.ID, post.ID == "", 2
+	if generated {
+		// Titles without letters or digits have no slug, so the key is used instead.
+		slug = model.Slug(post.Title)
+		if slug == "" && post.Title != "" {
+			slug = strings.ToLower(post.Key)
+		}
+		post.ID = slug
+
+		// Reserved slugs get a number, like the slugs of other blog posts.
+		for ; service.validation.IsReserved(post.ID) && number <= maxSlugNumber; number++ {
+			post.ID = model.SlugWithSuffix(slug, number)
+		}
+	}
+
+	errs := post.ValidateWith(service.validation)
+	if post.Status == model.StatusArchived {
+		errs = append(errs, globalModel.FieldError(globalModel.CodeNotAllowed, "status",
+			"A new blog post may only be a draft or published."))
+	}
 	if len(errs) > 0 {
-		return gloBalModel.Response{Entity: post, Errors: errs, StatusCode: 400}
+		return globalModel.Response{Entity: post, Errors: errs, StatusCode: 400}
 	}
 
 	post.CreationTimestamp = time.Now().UTC().Unix()
 	post.UpdateTimestamp = time.Now().UTC().Unix()
+	post.PublishedTimestamp = 0
+	if post.Status == model.StatusPublished {
+		post.PublishedTimestamp = post.CreationTimestamp
+	}
 
 	newPost, err := service.repo.Create(post)
+	for ; generated && errors.Is(err, postRepo.ErrAlreadyExists) && number <= maxSlugNumber; number++ {
+		// A retry of the same request finds the blog post that it has already created.
+		existingPost, found, getErr := service.repo.Get(post.ID)
+		if getErr == nil && found && isRetry(existingPost, post) {
+			break
+		}
+
+		post.ID = model.SlugWithSuffix(slug, number)
+		newPost, err = service.repo.Create(post)
+	}
 
 	if err != nil {
 		log.Println("An error occurred while creating a new blog post: ", err)
 
-		if requestFailure, ok := err.(awserr.RequestFailure); ok && requestFailure.Code() == "ConditionalCheckFailedException" {
+		if errors.Is(err, postRepo.ErrAlreadyExists) {
 			existingPost, found, err := service.repo.Get(newPost.ID)
 			if err != nil {
 				log.Println("An error occurred while fetching a blog post: ", err)
-				return gloBalModel.Response{Entity: newPost, Errors: []string{}, StatusCode: 500}
+				return globalModel.Response{Entity: newPost, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
 			}
 
-			newPost.CreationTimestamp = existingPost.CreationTimestamp
-			newPost.UpdateTimestamp = existingPost.UpdateTimestamp
-
-			if !found || existingPost != newPost {
-				return gloBalModel.Response{Entity: existingPost, Errors: []string{}, StatusCode: 409}
+			if !found || !isRetry(existingPost, newPost) {
+				return globalModel.Response{Entity: existingPost, Errors: []globalModel.Error{}, StatusCode: 409}
 			}
 
-			return gloBalModel.Response{Entity: newPost, Errors: []string{}, StatusCode: 200}
+			return globalModel.Response{Entity: existingPost, Errors: []globalModel.Error{}, StatusCode: 200}
 		}
 
-		return gloBalModel.Response{Entity: newPost, Errors: []string{}, StatusCode: 500}
+		return globalModel.Response{Entity: newPost, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
 	}
 
-	return gloBalModel.Response{Entity: newPost, Errors: []string{}, StatusCode: 200}
+	return globalModel.Response{Entity: newPost, Errors: []globalModel.Error{}, StatusCode: 200}
 }
 
-// Update updates an existing blog post.
-func (service *Service) Update(post model.BlogPost) gloBalModel.Response {
-	errs := post.Validate()
+// Update updates an existing blog post. A blog post without a status keeps its current one. Otherwise, the status
+// can only move from draft to published and from published to archived. Only drafts keep their scheduled time. Blog
+// posts in the trash cannot be updated. The author and the deletion time of a blog post are never taken from the
+// input.
+func (service *Service) Update(caller auth.Identity, post model.BlogPost) globalModel.Response {
+	post.Tags = model.NormalizeTags(post.Tags)
+	errs := post.ValidateWith(service.validation.ForUpdates())
 	if len(errs) > 0 {
-		return gloBalModel.Response{Entity: post, Errors: errs, StatusCode: 400}
+		return globalModel.Response{Entity: post, Errors: errs, StatusCode: 400}
+	}
+
+	currentPost, found, err := service.repo.Get(post.ID)
+	if err != nil {
+		log.Println("An error occurred while fetching a blog post: ", err)
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found || currentPost.HasStatus(model.StatusDeleted) {
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+
+	if !canChange(caller, currentPost) {
+		return forbidden(post, "The caller may only change their own blog posts.")
+	}
+
+	currentStatus := currentPost.CurrentStatus()
+	if post.Status == "" {
+		post.Status = currentStatus
+	}
+	if post.Status != model.StatusDraft {
+		post.ScheduledAt = 0
+	}
+	if !canSetStatus(caller, currentPost, post) {
+		return forbidden(post, "Only editors and admins may publish, archive or schedule blog posts.")
+	}
+	post.AuthorID = currentPost.AuthorID
+	post.Key = currentPost.Key
+	post.DeletedTimestamp = currentPost.DeletedTimestamp
+	// A stale revision fails with a conflict anyway, so the transition is only checked against the same revision.
+	if currentPost.Revision == post.Revision && !currentStatus.CanBecome(post.Status) {
+		err := globalModel.FieldError(globalModel.CodeInvalidTransition, "status",
+			fmt.Sprintf("The status cannot change from %s to %s.", currentStatus, post.Status))
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{err}, StatusCode: 400}
 	}
 
 	post.UpdateTimestamp = time.Now().UTC().Unix()
+	post.PublishedTimestamp = currentPost.PublishedTimestamp
+	if post.Status == model.StatusPublished && currentStatus != model.StatusPublished {
+		post.PublishedTimestamp = post.UpdateTimestamp
+	}
 	oldRevision := post.Revision
 	post.Revision = oldRevision + 1
 
@@ -75,98 +189,643 @@ func (service *Service) Update(post model.BlogPost) gloBalModel.Response {
 	if err != nil {
 		log.Println("An error occurred while updating a blog post: ", err)
 
-		if requestFailure, ok := err.(awserr.RequestFailure); ok && requestFailure.Code() == "ConditionalCheckFailedException" {
+		if errors.Is(err, postRepo.ErrNotFound) {
+			return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 404}
+		}
+		if errors.Is(err, postRepo.ErrRevisionMismatch) {
 			existingPost, found, err := service.repo.Get(post.ID)
 			if err != nil {
 				log.Println("An error occurred while fetching a blog post: ", err)
-				return gloBalModel.Response{Entity: post, Errors: []string{}, StatusCode: 500}
+				return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
 			}
 
 			post.CreationTimestamp = existingPost.CreationTimestamp
 			post.UpdateTimestamp = existingPost.UpdateTimestamp
+			post.PublishedTimestamp = existingPost.PublishedTimestamp
 			post.Revision = existingPost.Revision
 
-			if !found || existingPost != post {
-				return gloBalModel.Response{Entity: existingPost, Errors: []string{}, StatusCode: 409}
+			if !found || !existingPost.Equal(post) {
+				return globalModel.Response{Entity: existingPost, Errors: []globalModel.Error{}, StatusCode: 409}
 			}
 
-			return gloBalModel.Response{Entity: existingPost, Errors: []string{}, StatusCode: 200}
+			return globalModel.Response{Entity: existingPost, Errors: []globalModel.Error{}, StatusCode: 200}
 		}
 
-		return gloBalModel.Response{Entity: post, Errors: []string{}, StatusCode: 500}
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
 	}
 
-	return gloBalModel.Response{Entity: updatedPost, Errors: []string{}, StatusCode: 200}
+	return globalModel.Response{Entity: updatedPost, Errors: []globalModel.Error{}, StatusCode: 200}
 }
 
-// Delete deletes a blog post.
-func (service *Service) Delete(id string) gloBalModel.Response {
-	found, err := service.repo.Delete(id)
+// Patch applies a patch to a blog post and updates it the same way as Update. If a revision is given, the patch is
+// only applied if the blog post still has that revision; otherwise the current blog post is returned with a conflict.
+// The id, the revision and the timestamps of the blog post cannot be patched.
+func (service *Service) Patch(caller auth.Identity, id string, revision int64, patch model.Patch) globalModel.Response {
+	post, found, err := service.repo.Get(id)
+	if err != nil {
+		log.Println("An error occurred while fetching a blog post: ", err)
+		return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found || post.HasStatus(model.StatusDeleted) {
+		return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+	if !canChange(caller, post) {
+		return forbidden(post, "The caller may only change their own blog posts.")
+	}
+	if revision != 0 && post.Revision != revision {
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 409}
+	}
+
+	patchedPost, err := patch.Apply(post)
+	if err != nil {
+		log.Println("An error occurred while patching a blog post: ", err)
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{
+			globalModel.NewError(globalModel.CodeInvalidInput, "The patch cannot be applied to the blog post.")},
+			StatusCode: 400}
+	}
+
+	patchedPost.ID = post.ID
+	patchedPost.Revision = post.Revision
+	patchedPost.CreationTimestamp = post.CreationTimestamp
+	patchedPost.UpdateTimestamp = post.UpdateTimestamp
+	patchedPost.PublishedTimestamp = post.PublishedTimestamp
+	patchedPost.DeletedTimestamp = post.DeletedTimestamp
+
+	return service.Update(caller, patchedPost)
+}
+
+// Delete moves a blog post to the trash, where it's kept until it's restored or purged. If a revision is given, the
+// blog post is only deleted if it still has that revision; otherwise the current blog post is returned with a
+// conflict. A revision of 0 deletes the blog post whatever its revision.
+func (service *Service) Delete(caller auth.Identity, id string, revision int64) globalModel.Response {
+	post, found, err := service.repo.Get(id)
+	if err != nil {
+		log.Println("An error occurred while fetching a blog post: ", err)
+		return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found || post.HasStatus(model.StatusDeleted) {
+		return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+	if !canChange(caller, post) {
+		return forbidden(id, "The caller may only delete their own blog posts.")
+	}
+	if revision != 0 && post.Revision != revision {
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 409}
+	}
+
+	now := time.Now().UTC().Unix()
+	post.Status = model.StatusDeleted
+	post.DeletedTimestamp = now
+	post.UpdateTimestamp = now
+	oldRevision := post.Revision
+	post.Revision = oldRevision + 1
+
+	_, err = service.repo.Update(oldRevision, post)
 
 	if err != nil {
 		log.Println("An error occurred while deleting a blog post: ", err)
-		return gloBalModel.Response{Entity: id, Errors: []string{}, StatusCode: 500}
+
+		if errors.Is(err, postRepo.ErrNotFound) {
+			return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: 404}
+		}
+		if errors.Is(err, postRepo.ErrRevisionMismatch) {
+			currentPost, _, err := service.repo.Get(id)
+			if err != nil {
+				log.Println("An error occurred while fetching a blog post: ", err)
+				return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+			}
+
+			return globalModel.Response{Entity: currentPost, Errors: []globalModel.Error{}, StatusCode: 409}
+		}
+
+		return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
 	}
-	if !found {
-		return gloBalModel.Response{Entity: id, Errors: []string{}, StatusCode: 404}
+
+	return globalModel.Response{Entity: id, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// Restore brings a blog post back from the trash, with the status it had before it was deleted.
+func (service *Service) Restore(caller auth.Identity, id string) globalModel.Response {
+	post, found, err := service.repo.Get(id)
+	if err != nil {
+		log.Println("An error occurred while fetching a blog post: ", err)
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found || !post.HasStatus(model.StatusDeleted) {
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+	if !canChange(caller, post) {
+		return forbidden(post, "The caller may only restore their own blog posts.")
+	}
+
+	// Deleting a blog post is an update, so the revision before it keeps the old status.
+	oldPost, found, err := service.repo.GetRevision(id, post.Revision-1)
+	if err != nil {
+		log.Println("An error occurred while fetching a revision of a blog post: ", err)
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	post.Status = model.StatusDraft
+	if found && !oldPost.HasStatus(model.StatusDeleted) {
+		post.Status = oldPost.CurrentStatus()
+	}
+
+	post.DeletedTimestamp = 0
+	post.UpdateTimestamp = time.Now().UTC().Unix()
+	oldRevision := post.Revision
+	post.Revision = oldRevision + 1
+
+	restoredPost, err := service.repo.Update(oldRevision, post)
+
+	if err != nil {
+		log.Println("An error occurred while restoring a blog post: ", err)
+
+		if errors.Is(err, postRepo.ErrNotFound) {
+			return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 404}
+		}
+		if errors.Is(err, postRepo.ErrRevisionMismatch) {
+			return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 409}
+		}
+
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
 	}
 
-	return gloBalModel.Response{Entity: id, Errors: []string{}, StatusCode: 200}
+	return globalModel.Response{Entity: restoredPost, Errors: []globalModel.Error{}, StatusCode: 200}
 }
 
-// Get fetches a blog post.
-func (service *Service) Get(id string) gloBalModel.Response {
+// Get fetches a published blog post. Blog posts with any other status are reported as not found.
+func (service *Service) Get(id string) globalModel.Response {
 	post, found, err := service.repo.Get(id)
 
 	if err != nil {
 		log.Println("An error occurred while fetching a blog post: ", err)
-		return gloBalModel.Response{Entity: post, Errors: []string{}, StatusCode: 500}
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found || !post.HasStatus(model.StatusPublished) {
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+
+	return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// GetDraft fetches a blog post whatever its status, so that it can be edited. Blog posts in the trash are reported as
+// not found. Authors may only fetch the blog posts they have written.
+func (service *Service) GetDraft(caller auth.Identity, id string) globalModel.Response {
+	post, found, err := service.repo.Get(id)
+
+	if err != nil {
+		log.Println("An error occurred while fetching a blog post: ", err)
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found || post.HasStatus(model.StatusDeleted) {
+		return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+	if !canView(caller, post) {
+		return forbidden(model.BlogPost{}, "The caller may only read their own unpublished blog posts.")
+	}
+
+	return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// GetAll fetches the first page of published blog posts, newest first. If the page size is 0, the default page size is
+// used.
+func (service *Service) GetAll(pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching all blog posts",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			return service.repo.GetAll(model.StatusPublished, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetMore fetches the page of published blog posts that follows the page of the cursor, newest first. If the page size
+// is 0, the default page size is used.
+func (service *Service) GetMore(cursor string, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching more blog posts",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			return service.repo.GetMore(model.StatusPublished, cursor, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetAllByCategory fetches the first page of published blog posts of a category, newest first. If the page size is 0,
+// the default page size is used.
+func (service *Service) GetAllByCategory(category string, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching the blog posts of a category",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			return service.repo.GetAllByCategory(category, model.StatusPublished, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetMoreByCategory fetches the page of published blog posts of a category that follows the page of the cursor, newest
+// first. If the page size is 0, the default page size is used.
+func (service *Service) GetMoreByCategory(category string, cursor string, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching more blog posts of a category",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			return service.repo.GetMoreByCategory(category, model.StatusPublished, cursor, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetAllByAuthor fetches the first page of published blog posts of an author, newest first. If the page size is 0, the
+// default page size is used.
+func (service *Service) GetAllByAuthor(authorID string, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching the blog posts of an author",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			return service.repo.GetAllByAuthor(authorID, model.StatusPublished, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetMoreByAuthor fetches the page of published blog posts of an author that follows the page of the cursor, newest
+// first. If the page size is 0, the default page size is used.
+func (service *Service) GetMoreByAuthor(authorID string, cursor string, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching more blog posts of an author",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			return service.repo.GetMoreByAuthor(authorID, model.StatusPublished, cursor, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetAllByTag fetches the first page of published blog posts with a tag, newest first. If the page size is 0, the
+// default page size is used.
+func (service *Service) GetAllByTag(tag string, pageSize int64) globalModel.Response {
+	tag = model.NormalizeTag(tag)
+
+	return service.listPage(pageSize, "fetching the blog posts of a tag",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			return service.repo.GetAllByTag(tag, model.StatusPublished, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetMoreByTag fetches the page of published blog posts with a tag that follows the page of the cursor, newest first.
+// If the page size is 0, the default page size is used.
+func (service *Service) GetMoreByTag(tag string, cursor string, pageSize int64) globalModel.Response {
+	tag = model.NormalizeTag(tag)
+
+	return service.listPage(pageSize, "fetching more blog posts of a tag",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			return service.repo.GetMoreByTag(tag, model.StatusPublished, cursor, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetAllDrafts fetches the first page of draft blog posts, newest first. If the page size is 0, the default page size
+// is used. Authors only get the drafts they have written.
+func (service *Service) GetAllDrafts(caller auth.Identity, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching the draft blog posts",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			if !caller.HasRole(auth.RoleEditor) {
+				return service.repo.GetAllByAuthor(caller.Subject, model.StatusDraft, postRepo.NewestFirst, pageSize)
+			}
+			return service.repo.GetAll(model.StatusDraft, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetMoreDrafts fetches the page of draft blog posts that follows the page of the cursor, newest first. If the page
+// size is 0, the default page size is used. Authors only get the drafts they have written.
+func (service *Service) GetMoreDrafts(caller auth.Identity, cursor string, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching more draft blog posts",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			if !caller.HasRole(auth.RoleEditor) {
+				return service.repo.GetMoreByAuthor(caller.Subject, model.StatusDraft, cursor, postRepo.NewestFirst, pageSize)
+			}
+			return service.repo.GetMore(model.StatusDraft, cursor, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetAllTrash fetches the first page of blog posts in the trash, newest first. If the page size is 0, the default page
+// size is used. Authors only get the blog posts they have written.
+func (service *Service) GetAllTrash(caller auth.Identity, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching the blog posts in the trash",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			if !caller.HasRole(auth.RoleEditor) {
+				return service.repo.GetAllByAuthor(caller.Subject, model.StatusDeleted, postRepo.NewestFirst, pageSize)
+			}
+			return service.repo.GetAll(model.StatusDeleted, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetMoreTrash fetches the page of blog posts in the trash that follows the page of the cursor, newest first. If the
+// page size is 0, the default page size is used. Authors only get the blog posts they have written.
+func (service *Service) GetMoreTrash(caller auth.Identity, cursor string, pageSize int64) globalModel.Response {
+	return service.listPage(pageSize, "fetching more blog posts in the trash",
+		func(pageSize int64) ([]model.BlogPost, string, error) {
+			if !caller.HasRole(auth.RoleEditor) {
+				return service.repo.GetMoreByAuthor(caller.Subject, model.StatusDeleted, cursor, postRepo.NewestFirst, pageSize)
+			}
+			return service.repo.GetMore(model.StatusDeleted, cursor, postRepo.NewestFirst, pageSize)
+		})
+}
+
+// GetTags fetches every tag together with the number of published blog posts that have it.
+func (service *Service) GetTags() globalModel.Response {
+	counts, err := service.repo.GetTagCounts(model.StatusPublished)
+
+	if err != nil {
+		log.Println("An error occurred while fetching the tags: ", err)
+		return globalModel.Response{Entity: []model.TagCount{}, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+
+	return globalModel.Response{Entity: counts, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// PublishScheduled publishes the drafts that are scheduled to be published by now and returns their ids. Drafts that
+// change while they are being published are skipped, since the next run picks them up again.
+func (service *Service) PublishScheduled() globalModel.Response {
+	now := time.Now().UTC().Unix()
+	publishedIDs := []string{}
+
+	for {
+		posts, err := service.repo.GetDue(now, service.pageSize)
+		if err != nil {
+			log.Println("An error occurred while fetching the scheduled blog posts: ", err)
+			return globalModel.Response{Entity: publishedIDs, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+		}
+
+		published := false
+		for _, post := range posts {
+			oldRevision := post.Revision
+			post.Status = model.StatusPublished
+			post.PublishedTimestamp = now
+			post.UpdateTimestamp = now
+			post.ScheduledAt = 0
+			post.Revision = oldRevision + 1
+
+			_, err = service.repo.Update(oldRevision, post)
+			if errors.Is(err, postRepo.ErrRevisionMismatch) || errors.Is(err, postRepo.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				log.Println("An error occurred while publishing a scheduled blog post: ", err)
+				return globalModel.Response{Entity: publishedIDs, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+			}
+
+			publishedIDs = append(publishedIDs, post.ID)
+			published = true
+		}
+
+		if int64(len(posts)) < service.pageSize || !published {
+			break
+		}
+	}
+
+	return globalModel.Response{Entity: publishedIDs, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// GetRevisions fetches the revisions of a published blog post, newest first, starting with the current one. Only the
+// revisions that were published are included.
+func (service *Service) GetRevisions(id string) globalModel.Response {
+	return service.listRevisions(nil, id, model.StatusPublished)
+}
+
+// GetRevision fetches a revision of a published blog post. Revisions that were not published are reported as not
+// found.
+func (service *Service) GetRevision(id string, revision int64) globalModel.Response {
+	return service.findRevision(nil, id, revision, model.StatusPublished)
+}
+
+// GetDraftRevisions fetches the revisions of a blog post whatever its status, newest first, starting with the current
+// one. Authors may only fetch the revisions of the blog posts they have written.
+func (service *Service) GetDraftRevisions(caller auth.Identity, id string) globalModel.Response {
+	return service.listRevisions(&caller, id, "")
+}
+
+// GetDraftRevision fetches a revision of a blog post whatever its status. Authors may only fetch the revisions of the
+// blog posts they have written.
+func (service *Service) GetDraftRevision(caller auth.Identity, id string, revision int64) globalModel.Response {
+	return service.findRevision(&caller, id, revision, "")
+}
+
+// RestoreRevision brings back the content of a previous revision of a blog post as a new revision. The status and the
+// scheduled time of the blog post are kept.
+func (service *Service) RestoreRevision(caller auth.Identity, id string, revision int64) globalModel.Response {
+	currentPost, found, err := service.repo.Get(id)
+	if err != nil {
+		log.Println("An error occurred while fetching a blog post: ", err)
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found {
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+	if !canChange(caller, currentPost) {
+		return forbidden(currentPost, "The caller may only change their own blog posts.")
+	}
+	if currentPost.Revision == revision {
+		return globalModel.Response{Entity: currentPost, Errors: []globalModel.Error{}, StatusCode: 200}
+	}
+
+	oldPost, found, err := service.repo.GetRevision(id, revision)
+	if err != nil {
+		log.Println("An error occurred while fetching a revision of a blog post: ", err)
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
 	}
 	if !found {
-		return gloBalModel.Response{Entity: post, Errors: []string{}, StatusCode: 404}
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: 404}
 	}
 
-	return gloBalModel.Response{Entity: post, Errors: []string{}, StatusCode: 200}
+	post := currentPost
+	post.Title = oldPost.Title
+	post.Description = oldPost.Description
+	post.Tags = oldPost.Tags
+	post.Body = oldPost.Body
+	post.Template = oldPost.Template
+	post.Category = oldPost.Category
+
+	return service.Update(caller, post)
 }
 
-// GetAll fetches all blog posts (limit is 10).
-func (service *Service) GetAll() gloBalModel.Response {
-	posts, err := service.repo.GetAll(service.pageSize + 1)
+// GetDiff compares two revisions of a published blog post. Revisions that were not published are reported as not
+// found.
+func (service *Service) GetDiff(id string, from int64, to int64) globalModel.Response {
+	return service.compareRevisions(nil, id, from, to, model.StatusPublished)
+}
 
+// GetDraftDiff compares two revisions of a blog post whatever its status. Authors may only compare the revisions of the
+// blog posts they have written.
+func (service *Service) GetDraftDiff(caller auth.Identity, id string, from int64, to int64) globalModel.Response {
+	return service.compareRevisions(&caller, id, from, to, "")
+}
+
+// listRevisions fetches the revisions of a blog post, newest first, starting with the current one. If a status is
+// given, the current revision must have it and only the revisions with it are included. If a caller is given, they
+// must be allowed to read the blog post.
+func (service *Service) listRevisions(caller *auth.Identity, id string, status model.Status) globalModel.Response {
+	post, found, err := service.repo.Get(id)
+	if err != nil {
+		log.Println("An error occurred while fetching a blog post: ", err)
+		return globalModel.Response{Entity: []model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found || (status != "" && !post.HasStatus(status)) {
+		return globalModel.Response{Entity: []model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+	if caller != nil && !canView(*caller, post) {
+		return forbidden([]model.BlogPost{}, "The caller may only read their own unpublished blog posts.")
+	}
+
+	oldPosts, err := service.repo.GetRevisions(id)
 	if err != nil {
-		log.Println("An error occurred while fetching all blog posts: ", err)
-		return gloBalModel.Response{Entity: posts, Errors: []string{}, StatusCode: 500}
+		log.Println("An error occurred while fetching the revisions of a blog post: ", err)
+		return globalModel.Response{Entity: []model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+
+	posts := []model.BlogPost{post}
+	for _, oldPost := range oldPosts {
+		if status == "" || oldPost.HasStatus(status) {
+			posts = append(posts, oldPost)
+		}
+	}
+
+	return globalModel.Response{Entity: posts, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// compareRevisions compares two revisions of a blog post, which may also be the current one. If a status is given,
+// the current revision and both of the compared ones must have it. If a caller is given, they must be allowed to read
+// the blog post.
+func (service *Service) compareRevisions(caller *auth.Identity, id string, from int64, to int64,
+	status model.Status) globalModel.Response {
+	if from <= 0 || to <= 0 {
+		err := globalModel.NewError(globalModel.CodeTooSmall, "The revisions must be positive numbers.")
+		return globalModel.Response{Entity: model.Diff{}, Errors: []globalModel.Error{err}, StatusCode: 400}
+	}
+
+	fromResponse := service.findRevision(caller, id, from, status)
+	if fromResponse.StatusCode != 200 {
+		return globalModel.Response{Entity: model.Diff{}, Errors: fromResponse.Errors, StatusCode: fromResponse.StatusCode}
+	}
+	toResponse := service.findRevision(caller, id, to, status)
+	if toResponse.StatusCode != 200 {
+		return globalModel.Response{Entity: model.Diff{}, Errors: toResponse.Errors, StatusCode: toResponse.StatusCode}
+	}
+
+	result := model.Compare(fromResponse.Entity.(model.BlogPost), toResponse.Entity.(model.BlogPost))
+
+	return globalModel.Response{Entity: result, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// findRevision fetches a revision of a blog post, which may also be the current one. If a status is given, both the
+// current revision and the requested one must have it. If a caller is given, they must be allowed to read the blog
+// post.
+func (service *Service) findRevision(caller *auth.Identity, id string, revision int64,
+	status model.Status) globalModel.Response {
+	post, found, err := service.repo.Get(id)
+	if err != nil {
+		log.Println("An error occurred while fetching a blog post: ", err)
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+	if !found || (status != "" && !post.HasStatus(status)) {
+		return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: 404}
+	}
+	if caller != nil && !canView(*caller, post) {
+		return forbidden(model.BlogPost{}, "The caller may only read their own unpublished blog posts.")
+	}
+
+	if post.Revision != revision {
+		post, found, err = service.repo.GetRevision(id, revision)
+		if err != nil {
+			log.Println("An error occurred while fetching a revision of a blog post: ", err)
+			return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+		}
+		if !found || (status != "" && !post.HasStatus(status)) {
+			return globalModel.Response{Entity: model.BlogPost{}, Errors: []globalModel.Error{}, StatusCode: 404}
+		}
 	}
 
-	hasMore := int64(len(posts)) > service.pageSize
-	page := model.Page{Posts: posts}
+	return globalModel.Response{Entity: post, Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// PurgeTrash permanently deletes the blog posts that have been in the trash for longer than the retention period and
+// returns their ids. Blog posts that change while they are being purged (e.g. because they were restored) are kept.
+func (service *Service) PurgeTrash() globalModel.Response {
+	cutoff := time.Now().UTC().Add(-service.trashRetention).Unix()
+	purgedIDs := []string{}
+
+	for {
+		posts, err := service.repo.GetDeleted(cutoff, service.pageSize)
+		if err != nil {
+			log.Println("An error occurred while fetching the blog posts in the trash: ", err)
+			return globalModel.Response{Entity: purgedIDs, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+		}
 
-	if hasMore {
-		page.Posts = page.Posts[:service.pageSize]
-		lastItem := page.Posts[service.pageSize-1]
-		page.Cursor = lastItem.ID
+		purged := false
+		for _, post := range posts {
+			found, err := service.repo.Delete(post.Revision, post.ID)
+			if errors.Is(err, postRepo.ErrRevisionMismatch) {
+				continue
+			}
+			if err != nil {
+				log.Println("An error occurred while purging a blog post: ", err)
+				return globalModel.Response{Entity: purgedIDs, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+			}
+			if found {
+				purgedIDs = append(purgedIDs, post.ID)
+				purged = true
+			}
+		}
+
+		if int64(len(posts)) < service.pageSize || !purged {
+			break
+		}
 	}
 
-	return gloBalModel.Response{Entity: page, Errors: []string{}, StatusCode: 200}
+	return globalModel.Response{Entity: purgedIDs, Errors: []globalModel.Error{}, StatusCode: 200}
 }
 
-// GetMore fetches more blog posts (limit is 10).
-func (service *Service) GetMore(lastID string) gloBalModel.Response {
-	posts, err := service.repo.GetMore(lastID, service.pageSize+1)
+// listPage loads a page of blog posts and returns it as a response. The action describes the operation in the logs.
+func (service *Service) listPage(pageSize int64, action string,
+	load func(pageSize int64) ([]model.BlogPost, string, error)) globalModel.Response {
+	pageSize, errs := service.resolvePageSize(pageSize)
+	if len(errs) > 0 {
+		return globalModel.Response{Entity: newPage(nil, ""), Errors: errs, StatusCode: 400}
+	}
 
+	posts, cursor, err := load(pageSize)
+
+	if errors.Is(err, postRepo.ErrInvalidCursor) {
+		err := globalModel.FieldError(globalModel.CodeInvalid, "cursor", "The cursor is not valid.")
+		return globalModel.Response{Entity: newPage(nil, ""), Errors: []globalModel.Error{err}, StatusCode: 400}
+	}
 	if err != nil {
-		log.Println("An error occurred while fetching more blog posts: ", err)
-		return gloBalModel.Response{Entity: posts, Errors: []string{}, StatusCode: 500}
+		log.Println("An error occurred while "+action+": ", err)
+		return globalModel.Response{Entity: posts, Errors: []globalModel.Error{}, StatusCode: errorStatusCode(err)}
+	}
+
+	return globalModel.Response{Entity: newPage(posts, cursor), Errors: []globalModel.Error{}, StatusCode: 200}
+}
+
+// resolvePageSize returns the page size to use for a request, or errors if the requested one is not allowed.
+func (service *Service) resolvePageSize(pageSize int64) (int64, []globalModel.Error) {
+	if pageSize == 0 {
+		return service.pageSize, []globalModel.Error{}
+	}
+	if pageSize < 0 || pageSize > service.maxPageSize {
+		return 0, []globalModel.Error{globalModel.FieldError(globalModel.CodeOutOfRange, "pageSize",
+			fmt.Sprintf("The page size must be between 1 and %d.", service.maxPageSize))}
 	}
 
-	hasMore := int64(len(posts)) > service.pageSize
-	page := model.Page{Posts: posts}
+	return pageSize, []globalModel.Error{}
+}
+
+// isRetry checks if an existing blog post is the same as a new one, apart from the values that are set when it's
+// created, so that creating the same blog post again succeeds.
+func isRetry(existingPost model.BlogPost, post model.BlogPost) bool {
+	post.Key = existingPost.Key
+	post.CreationTimestamp = existingPost.CreationTimestamp
+	post.UpdateTimestamp = existingPost.UpdateTimestamp
+	post.PublishedTimestamp = existingPost.PublishedTimestamp
+
+	return existingPost.Equal(post)
+}
 
-	if hasMore {
-		page.Posts = page.Posts[:service.pageSize]
-		lastItem := page.Posts[service.pageSize-1]
-		page.Cursor = lastItem.ID
+// newPage creates a page of blog posts.
+func newPage(posts []model.BlogPost, cursor string) model.Page {
+	if posts == nil {
+		posts = []model.BlogPost{}
 	}
 
-	return gloBalModel.Response{Entity: page, Errors: []string{}, StatusCode: 200}
+	return model.Page{Posts: posts, Cursor: cursor, HasMore: cursor != ""}
+}
+
+// errorStatusCode returns the status code for an unexpected repository error.
+func errorStatusCode(err error) int {
+	switch {
+	case errors.Is(err, postRepo.ErrThrottled):
+		return 503
+	case errors.Is(err, postRepo.ErrTooLarge):
+		return 413
+	default:
+		return 500
+	}
 }
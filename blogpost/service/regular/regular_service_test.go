@@ -2,18 +2,25 @@ package regular
 
 import (
 	"errors"
+	"fmt"
+	"os"
 	"reflect"
 	"testing"
-
-	"github.com/aws/aws-sdk-go/aws/awserr"
+	"time"
 
 	"github.com/stretchr/testify/mock"
 
 	"github.com/printezisn/serverless-blog-back/blogpost/model"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	globalModel "github.com/printezisn/serverless-blog-back/global/model"
 
+	postRepo "github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
 	repoMocks "github.com/printezisn/serverless-blog-back/blogpost/repository/mocks"
 )
 
+// editor is the caller of the tests that may change every blog post.
+var editor = auth.Identity{Subject: "editor", Username: "editor", Groups: []string{"editor"}}
+
 // TestNew tests that the New method creates the service properly.
 func TestNew(t *testing.T) {
 	repo := new(repoMocks.Repo)
@@ -24,13 +31,25 @@ func TestNew(t *testing.T) {
 	}
 }
 
+// TestNewWithTrashRetention tests that the New method reads the retention period of the trash from the environment.
+func TestNewWithTrashRetention(t *testing.T) {
+	os.Setenv("TRASH_RETENTION_DAYS", "7")
+	defer os.Unsetenv("TRASH_RETENTION_DAYS")
+
+	service := New(new(repoMocks.Repo))
+
+	if service.trashRetention != 7*24*time.Hour {
+		t.Error("The retention period of the trash was expected to be 7 days, but it was ", service.trashRetention)
+	}
+}
+
 // TestCreateWithValidationErrors tests that the Create method returns errors when the input is invalid.
 func TestCreateWithValidationErrors(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
 	post := model.BlogPost{}
 
-	response := service.Create(post)
+	response := service.Create(editor, post)
 
 	if response.StatusCode != 400 {
 		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
@@ -40,17 +59,39 @@ func TestCreateWithValidationErrors(t *testing.T) {
 	}
 }
 
+// TestCreateWithValidationPolicy tests that the Create method validates blog posts against the validation policy of
+// the environment.
+func TestCreateWithValidationPolicy(t *testing.T) {
+	os.Setenv("POST_RESERVED_IDS", "drafts")
+	defer os.Unsetenv("POST_RESERVED_IDS")
+
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "drafts", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+	if len(response.Errors) != 1 || response.Errors[0].Code != globalModel.CodeReserved {
+		t.Error("The response was expected to contain a reserved id error, but it contained ", response.Errors)
+	}
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
 // TestCreateWithNonConditionalError tests that the Create method returns the correct response when an unexpected
 // error occurs.
 func TestCreateWithNonConditionalError(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
 
 	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, errors.New("unexpected error"))
 
-	response := service.Create(post)
+	response := service.Create(editor, post)
 
 	if response.StatusCode != 500 {
 		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
@@ -62,18 +103,15 @@ func TestCreateWithNonConditionalError(t *testing.T) {
 func TestCreateWithConditionalErrorAndConflicts(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
-	storedPost := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
-		Category: "category", Revision: 2}
-
-	err := awserr.New("ConditionalCheckFailedException", "error", errors.New("error"))
-	requestFailure := awserr.NewRequestFailure(err, 400, "1")
+	storedPost := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 2, Status: model.StatusPublished}
 
-	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, requestFailure)
+	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, postRepo.ErrAlreadyExists)
 	repo.On("Get", post.ID).Return(storedPost, true, nil)
 
-	response := service.Create(post)
+	response := service.Create(editor, post)
 
 	if response.StatusCode != 409 {
 		t.Errorf("The status code was expected to be 409, but it was %d.", response.StatusCode)
@@ -85,23 +123,20 @@ func TestCreateWithConditionalErrorAndConflicts(t *testing.T) {
 func TestCreateWithConditionalErrorAndNoConflicts(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
-		Category: "category", Revision: 1}
-	storedPost := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
-		Category: "category", Revision: 1}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 1, Status: model.StatusPublished}
+	storedPost := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 1, Status: model.StatusPublished}
 
-	err := awserr.New("ConditionalCheckFailedException", "error", errors.New("error"))
-	requestFailure := awserr.NewRequestFailure(err, 400, "1")
-
-	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, requestFailure)
+	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, postRepo.ErrAlreadyExists)
 	repo.On("Get", post.ID).Return(storedPost, true, nil)
 
-	response := service.Create(post)
+	response := service.Create(editor, post)
 
 	if response.StatusCode != 200 {
 		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
-	if response.Entity != storedPost {
+	if !reflect.DeepEqual(response.Entity, storedPost) {
 		t.Error("The entity was expected to be ", storedPost, " but it was ", response.Entity)
 	}
 }
@@ -111,48 +146,314 @@ func TestCreateWithConditionalErrorAndNoConflicts(t *testing.T) {
 func TestCreateWithConditionalErrorAndFailure(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
 
-	err := awserr.New("ConditionalCheckFailedException", "error", errors.New("error"))
-	requestFailure := awserr.NewRequestFailure(err, 400, "1")
-
-	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, requestFailure)
+	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, postRepo.ErrAlreadyExists)
 	repo.On("Get", post.ID).Return(post, false, errors.New("unexpected error"))
 
-	response := service.Create(post)
+	response := service.Create(editor, post)
 
 	if response.StatusCode != 500 {
 		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
 	}
 }
 
+// TestCreateWithThrottling tests that the Create method returns the correct response when the repository is
+// throttled.
+func TestCreateWithThrottling(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 1}
+
+	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, fmt.Errorf("%w: error", postRepo.ErrThrottled))
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 503 {
+		t.Errorf("The status code was expected to be 503, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestCreateWithTooLargePost tests that the Create method returns the correct response when the blog post is too
+// large to be stored.
+func TestCreateWithTooLargePost(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 1}
+
+	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, fmt.Errorf("%w: error", postRepo.ErrTooLarge))
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 413 {
+		t.Errorf("The status code was expected to be 413, but it was %d.", response.StatusCode)
+	}
+}
+
 // TestCreateWithSuccess tests that the Create method returns the correct response when the operation is successful.
 func TestCreateWithSuccess(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
 
 	repo.On("Create", mock.MatchedBy(matchedByPost(post))).Return(post, nil)
 
-	response := service.Create(post)
+	response := service.Create(editor, post)
 
 	if response.StatusCode != 200 {
 		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
-	if response.Entity != post {
+	if !reflect.DeepEqual(response.Entity, post) {
 		t.Error("The entity was expected to be ", post, " but it was ", response.Entity)
 	}
 }
 
+// TestCreateWithArchivedStatus tests that the Create method doesn't allow new blog posts to be archived.
+func TestCreateWithArchivedStatus(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusArchived}
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestCreateWithDraftStatus tests that the Create method keeps the draft status and doesn't set the published
+// timestamp.
+func TestCreateWithDraftStatus(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusDraft}
+
+	repo.On("Create", mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Status == model.StatusDraft && actualPost.PublishedTimestamp == 0
+	})).Return(post, nil)
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestCreateWithoutStatus tests that the Create method publishes a blog post without a status.
+func TestCreateWithoutStatus(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+
+	repo.On("Create", mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Status == model.StatusPublished && actualPost.PublishedTimestamp > 0
+	})).Return(post, nil)
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestCreateAsAuthor tests that the Create method keeps the blog posts of authors as drafts and records them as
+// their author.
+func TestCreateAsAuthor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	author := auth.Identity{Subject: "author", Groups: []string{"author"}}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+
+	repo.On("Create", mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Status == model.StatusDraft && actualPost.AuthorID == "author"
+	})).Return(post, nil)
+
+	response := service.Create(author, post)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestCreateWithGeneratedSlug tests that the Create method gives a blog post without an id the slug of its title and
+// a key.
+func TestCreateWithGeneratedSlug(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{Title: "Καλημέρα, Κόσμε!", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+
+	repo.On("Create", mock.Anything).Return(func(post model.BlogPost) model.BlogPost { return post }, nil)
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if newPost := response.Entity.(model.BlogPost); newPost.ID != "kalimera-kosme" || len(newPost.Key) != 26 {
+		t.Errorf("The id and the key were expected to be kalimera-kosme and a ULID, but they were %s and %s.",
+			newPost.ID, newPost.Key)
+	}
+}
+
+// TestCreateWithSlugCollision tests that the Create method adds a number to a generated slug that another blog post
+// has, and that creating the same blog post again returns the one that already exists.
+func TestCreateWithSlugCollision(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{Title: "Hello World", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+	otherPost := model.BlogPost{ID: "hello-world", Title: "Hello World", Body: "other", Revision: 1}
+	sameAsPost := model.BlogPost{ID: "hello-world-2", Key: "key", Title: "Hello World", Description: "descr",
+		Tags: model.Tags{"tags"}, Body: "body", Template: "template", Category: "category", Revision: 1,
+		AuthorID: editor.Subject, Status: model.StatusPublished}
+	withID := func(id string) interface{} {
+		return mock.MatchedBy(func(post model.BlogPost) bool { return post.ID == id })
+	}
+
+	repo.On("Create", withID("hello-world")).Return(post, postRepo.ErrAlreadyExists)
+	repo.On("Get", "hello-world").Return(otherPost, true, nil)
+	repo.On("Create", withID("hello-world-2")).Return(sameAsPost, postRepo.ErrAlreadyExists)
+	repo.On("Get", "hello-world-2").Return(sameAsPost, true, nil)
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, sameAsPost) {
+		t.Error("The entity was expected to be ", sameAsPost, " but it was ", response.Entity)
+	}
+	repo.AssertNotCalled(t, "Create", withID("hello-world-3"))
+}
+
+// TestCreateWithReservedSlug tests that the Create method adds a number to a generated slug that is reserved.
+func TestCreateWithReservedSlug(t *testing.T) {
+	os.Setenv("POST_RESERVED_IDS", "trash,trash-2")
+	defer os.Unsetenv("POST_RESERVED_IDS")
+
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{Title: "Trash", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+
+	repo.On("Create", mock.Anything).Return(func(post model.BlogPost) model.BlogPost { return post }, nil)
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if newPost := response.Entity.(model.BlogPost); newPost.ID != "trash-3" {
+		t.Errorf("The id was expected to be trash-3, but it was %s.", newPost.ID)
+	}
+}
+
+// TestCreateWithInvalidID tests that the Create method rejects ids that are not slugs.
+func TestCreateWithInvalidID(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "Hello World", Title: "title", Description: "descr", Tags: model.Tags{"tags"},
+		Body: "body", Template: "template", Category: "category", Revision: 1}
+
+	response := service.Create(editor, post)
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+	if len(response.Errors) != 1 || response.Errors[0].Field != "id" {
+		t.Error("The response was expected to contain an error for the id, but it contained ", response.Errors)
+	}
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestCreateWithoutPermission tests that the Create method rejects readers and authors that publish blog posts.
+func TestCreateWithoutPermission(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+	published := post
+	published.Status = model.StatusPublished
+	scheduled := post
+	scheduled.Status = model.StatusDraft
+	scheduled.ScheduledAt = time.Now().Add(time.Hour).Unix()
+
+	testCases := []struct {
+		caller auth.Identity
+		post   model.BlogPost
+	}{
+		{auth.Identity{Subject: "reader"}, post},
+		{auth.Identity{Subject: "author", Groups: []string{"author"}}, published},
+		{auth.Identity{Subject: "author", Groups: []string{"author"}}, scheduled},
+	}
+
+	for _, testCase := range testCases {
+		response := service.Create(testCase.caller, testCase.post)
+
+		if response.StatusCode != 403 {
+			t.Errorf("The status code was expected to be 403, but it was %d.", response.StatusCode)
+		}
+	}
+	repo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// TestUpdateAsAuthor tests that the Update method lets authors change their own drafts, but not the blog posts of
+// others or their status.
+func TestUpdateAsAuthor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	author := auth.Identity{Subject: "author", Groups: []string{"author"}}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Status: model.StatusDraft, Revision: 1}
+	ownPost := post
+	ownPost.AuthorID = "author"
+	otherPost := post
+	otherPost.ID = "other"
+	otherPost.AuthorID = "other"
+	published := post
+	published.Status = model.StatusPublished
+
+	repo.On("Get", "id").Return(ownPost, true, nil)
+	repo.On("Get", "other").Return(otherPost, true, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.AuthorID == "author"
+	})).Return(ownPost, nil)
+
+	testCases := []struct {
+		post       model.BlogPost
+		statusCode int
+	}{
+		{post, 200},
+		{otherPost, 403},
+		{published, 403},
+	}
+
+	for _, testCase := range testCases {
+		response := service.Update(author, testCase.post)
+
+		if response.StatusCode != testCase.statusCode {
+			t.Errorf("The status code for %s was expected to be %d, but it was %d.", testCase.post.ID,
+				testCase.statusCode, response.StatusCode)
+		}
+	}
+}
+
 // TestUpdateWithValidationErrors tests that the Update method returns errors when the input is invalid.
 func TestUpdateWithValidationErrors(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
 	post := model.BlogPost{}
 
-	response := service.Update(post)
+	response := service.Update(editor, post)
 
 	if response.StatusCode != 400 {
 		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
@@ -167,14 +468,15 @@ func TestUpdateWithValidationErrors(t *testing.T) {
 func TestUpdateWithNonConditionalError(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
-	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 2}
 
+	repo.On("Get", post.ID).Return(post, true, nil)
 	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(postUpdate, errors.New("unexpected error"))
 
-	response := service.Update(post)
+	response := service.Update(editor, post)
 
 	if response.StatusCode != 500 {
 		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
@@ -186,20 +488,17 @@ func TestUpdateWithNonConditionalError(t *testing.T) {
 func TestUpdateWithConditionalErrorAndConflicts(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
-	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
-		Category: "category", Revision: 2}
-	storedPost := model.BlogPost{ID: "id", Title: "title", Description: "descr2", Tags: "tags", Body: "body", Template: "template",
+	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 2}
+	storedPost := model.BlogPost{ID: "id", Title: "title", Description: "descr2", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 2, Status: model.StatusPublished}
 
-	err := awserr.New("ConditionalCheckFailedException", "error", errors.New("error"))
-	requestFailure := awserr.NewRequestFailure(err, 400, "1")
-
-	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(postUpdate, requestFailure)
+	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(postUpdate, postRepo.ErrRevisionMismatch)
 	repo.On("Get", post.ID).Return(storedPost, true, nil)
 
-	response := service.Update(post)
+	response := service.Update(editor, post)
 
 	if response.StatusCode != 409 {
 		t.Errorf("The status code was expected to be 409, but it was %d.", response.StatusCode)
@@ -211,25 +510,22 @@ func TestUpdateWithConditionalErrorAndConflicts(t *testing.T) {
 func TestUpdateWithConditionalErrorAndNoConflicts(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
-	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
-		Category: "category", Revision: 2}
-	storedPost := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 2}
+	storedPost := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 2, Status: model.StatusPublished}
 
-	err := awserr.New("ConditionalCheckFailedException", "error", errors.New("error"))
-	requestFailure := awserr.NewRequestFailure(err, 400, "1")
-
-	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(postUpdate, requestFailure)
+	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(postUpdate, postRepo.ErrRevisionMismatch)
 	repo.On("Get", post.ID).Return(storedPost, true, nil)
 
-	response := service.Update(post)
+	response := service.Update(editor, post)
 
 	if response.StatusCode != 200 {
 		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
-	if response.Entity != storedPost {
+	if !reflect.DeepEqual(response.Entity, storedPost) {
 		t.Error("The entity was expected to be ", storedPost, " but it was ", response.Entity)
 	}
 }
@@ -239,215 +535,565 @@ func TestUpdateWithConditionalErrorAndNoConflicts(t *testing.T) {
 func TestUpdateWithConditionalErrorAndFailure(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
-	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 2}
 
-	err := awserr.New("ConditionalCheckFailedException", "error", errors.New("error"))
-	requestFailure := awserr.NewRequestFailure(err, 400, "1")
-
-	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(postUpdate, requestFailure)
+	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(postUpdate, postRepo.ErrRevisionMismatch)
 	repo.On("Get", post.ID).Return(post, false, errors.New("unexpected error"))
 
-	response := service.Update(post)
+	response := service.Update(editor, post)
 
 	if response.StatusCode != 500 {
 		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
 	}
 }
 
+// TestUpdateWithNotFound tests that the Update method returns the correct response when the blog post doesn't exist.
+func TestUpdateWithNotFound(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 1}
+	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 2}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(model.BlogPost{}, postRepo.ErrNotFound)
+
+	response := service.Update(editor, post)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
 // TestUpdateWithSuccess tests that the Update method returns the correct response when the operation is successful.
 func TestUpdateWithSuccess(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 1}
-	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
+	postUpdate := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
 		Category: "category", Revision: 2}
 
+	repo.On("Get", post.ID).Return(post, true, nil)
 	repo.On("Update", int64(1), mock.MatchedBy(matchedByPost(postUpdate))).Return(postUpdate, nil)
 
-	response := service.Update(post)
+	response := service.Update(editor, post)
 
 	if response.StatusCode != 200 {
 		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
-	if response.Entity != postUpdate {
+	if !reflect.DeepEqual(response.Entity, postUpdate) {
 		t.Error("The entity was expected to be ", post, " but it was ", response.Entity)
 	}
 }
 
-// TestDeleteWithError tests that the Delete method returns the correct response when an unexpected error occurs.
-func TestDeleteWithError(t *testing.T) {
-	repo := new(repoMocks.Repo)
-	service := New(repo)
-	id := "id"
+// TestUpdateWithInvalidStatusTransition tests that the Update method rejects status changes that the workflow
+// doesn't allow.
+func TestUpdateWithInvalidStatusTransition(t *testing.T) {
+	transitions := []struct {
+		from model.Status
+		to   model.Status
+	}{
+		{model.StatusDraft, model.StatusArchived},
+		{model.StatusPublished, model.StatusDraft},
+		{model.StatusArchived, model.StatusDraft},
+		{model.StatusArchived, model.StatusPublished},
+	}
 
-	repo.On("Delete", id).Return(false, errors.New("unexpected error"))
+	for _, transition := range transitions {
+		repo := new(repoMocks.Repo)
+		service := New(repo)
+		post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+			Template: "template", Category: "category", Revision: 1, Status: transition.to}
+		storedPost := post
+		storedPost.Status = transition.from
 
-	response := service.Delete(id)
+		repo.On("Get", post.ID).Return(storedPost, true, nil)
 
-	if response.StatusCode != 500 {
-		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+		response := service.Update(editor, post)
+
+		if response.StatusCode != 400 {
+			t.Errorf("The status code from %s to %s was expected to be 400, but it was %d.", transition.from,
+				transition.to, response.StatusCode)
+		}
 	}
 }
 
-// TestDeleteWithNotFound tests that the Delete method returns the correct response when the blog post is not found.
-func TestDeleteWithNotFound(t *testing.T) {
+// TestUpdateWithPublishing tests that the Update method sets the published timestamp when a draft is published.
+func TestUpdateWithPublishing(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	id := "id"
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusPublished}
+	storedPost := post
+	storedPost.Status = model.StatusDraft
 
-	repo.On("Delete", id).Return(false, nil)
+	repo.On("Get", post.ID).Return(storedPost, true, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Status == model.StatusPublished && actualPost.PublishedTimestamp > 0
+	})).Return(post, nil)
 
-	response := service.Delete(id)
+	response := service.Update(editor, post)
 
-	if response.StatusCode != 404 {
-		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
 }
 
-// TestDeleteWithFound tests that the Delete method returns the correct response when the blog post is found.
-func TestDeleteWithFound(t *testing.T) {
+// TestUpdateWithoutStatus tests that the Update method keeps the current status and published timestamp of a blog
+// post when no status is given.
+func TestUpdateWithoutStatus(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	id := "id"
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+	storedPost := post
+	storedPost.Status = model.StatusArchived
+	storedPost.PublishedTimestamp = 100
 
-	repo.On("Delete", id).Return(true, nil)
+	repo.On("Get", post.ID).Return(storedPost, true, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Status == model.StatusArchived && actualPost.PublishedTimestamp == 100
+	})).Return(storedPost, nil)
 
-	response := service.Delete(id)
+	response := service.Update(editor, post)
 
 	if response.StatusCode != 200 {
 		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
 }
 
-// TestGetWithError tests that the Get method returns the correct response when an unexpected error occurs.
-func TestGetWithError(t *testing.T) {
+// TestUpdateWithDeletedTimestamp tests that the Update method keeps the deletion time of a blog post, so that it
+// cannot be set by the caller.
+func TestUpdateWithDeletedTimestamp(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	id := "id"
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Status: model.StatusPublished, Revision: 1,
+		DeletedTimestamp: 100}
+	storedPost := post
+	storedPost.DeletedTimestamp = 0
 
-	repo.On("Get", id).Return(model.BlogPost{}, false, errors.New("unexpected error"))
+	repo.On("Get", post.ID).Return(storedPost, true, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.DeletedTimestamp == 0
+	})).Return(storedPost, nil)
 
-	response := service.Get(id)
+	response := service.Update(editor, post)
 
-	if response.StatusCode != 500 {
-		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
 }
 
-// TestGetWithNotFound tests that the Get method returns the correct response when the blog post is not found.
-func TestGetWithNotFound(t *testing.T) {
+// TestUpdateWithMissingPost tests that the Update method returns the correct response when the blog post doesn't
+// exist before the update.
+func TestUpdateWithMissingPost(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
-		Category: "category", Revision: 1}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
 
-	repo.On("Get", post.ID).Return(post, false, nil)
+	repo.On("Get", post.ID).Return(model.BlogPost{}, false, nil)
 
-	response := service.Get(post.ID)
+	response := service.Update(editor, post)
 
 	if response.StatusCode != 404 {
 		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
 	}
 }
 
-// TestGetWithFound tests that the Get method returns the correct response when the blog post is found.
-func TestGetWithFound(t *testing.T) {
+// TestDeleteWithError tests that the Delete method returns the correct response when an unexpected error occurs.
+func TestDeleteWithError(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: "tags", Body: "body", Template: "template",
-		Category: "category", Revision: 1}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusPublished}
 
 	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("Update", int64(1), mock.AnythingOfType("model.BlogPost")).
+		Return(model.BlogPost{}, errors.New("unexpected error"))
 
-	response := service.Get(post.ID)
+	response := service.Delete(editor, post.ID, 0)
 
-	if response.StatusCode != 200 {
-		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
-	}
-	if response.Entity != post {
-		t.Error("The entity was expected to be ", post, " but it was ", response.Entity)
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
 	}
 }
 
-// TestGetAllWithError tests that the GetAll method returns the correct response when there is an unexpected error.
-func TestGetAllWithError(t *testing.T) {
+// TestDeleteWithNotFound tests that the Delete method returns the correct response when the blog post is not found.
+func TestDeleteWithNotFound(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
+	id := "id"
 
-	repo.On("GetAll", service.pageSize+1).Return([]model.BlogPost{}, errors.New("unexpected error"))
+	repo.On("Get", id).Return(model.BlogPost{}, false, nil)
 
-	response := service.GetAll()
+	response := service.Delete(editor, id, 0)
 
-	if response.StatusCode != 500 {
-		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
 	}
 }
 
-// TestGetAllWithFewItems tests that the GetAll method returns the correct response when there are only a few items.
-func TestGetAllWithFewItems(t *testing.T) {
+// TestDeleteWithFound tests that the Delete method moves the blog post to the trash when it's found.
+func TestDeleteWithFound(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	posts := []model.BlogPost{
-		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: "tags1", Body: "body1", Template: "template1",
-			Category: "category1", Revision: 1},
-		model.BlogPost{ID: "id2", Title: "title2", Description: "descr2", Tags: "tags2", Body: "body2", Template: "template2",
-			Category: "category2", Revision: 1},
-	}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusPublished}
 
-	repo.On("GetAll", service.pageSize+1).Return(posts, nil)
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Status == model.StatusDeleted && actualPost.DeletedTimestamp > 0 && actualPost.Revision == 2
+	})).Return(post, nil)
 
-	response := service.GetAll()
+	response := service.Delete(editor, post.ID, 1)
 
 	if response.StatusCode != 200 {
 		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
-	if _, ok := response.Entity.(model.Page); !ok {
-		t.Error("The entity was expected to be of type Page, but it was ", reflect.TypeOf(response.Entity))
-	}
+	repo.AssertNotCalled(t, "Delete", int64(1), post.ID)
+}
 
-	page, _ := response.Entity.(model.Page)
-	if !compareSlices(page.Posts, posts) {
-		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+// TestDeleteWithWrongRevision tests that the Delete method returns the current blog post with a conflict when it has
+// a different revision.
+func TestDeleteWithWrongRevision(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 3, Status: model.StatusPublished}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.Delete(editor, post.ID, 2)
+
+	if response.StatusCode != 409 {
+		t.Errorf("The status code was expected to be 409, but it was %d.", response.StatusCode)
 	}
-	if page.Cursor != "" {
-		t.Error("The cursor was expected to be empty but it was ", page.Cursor)
+	if !reflect.DeepEqual(response.Entity, post) {
+		t.Error("The entity was expected to be ", post, " but it was ", response.Entity)
 	}
 }
 
-// TestGetAllWithMoreItems tests that the GetAll method returns the correct response when there are more items.
-func TestGetAllWithMoreItems(t *testing.T) {
+// TestDeleteWithConcurrentUpdate tests that the Delete method returns the current blog post with a conflict when it
+// changes before it's moved to the trash.
+func TestDeleteWithConcurrentUpdate(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	service.pageSize = 1
-	posts := []model.BlogPost{
-		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: "tags1", Body: "body1", Template: "template1",
-			Category: "category1", Revision: 1, CreationTimestamp: 2},
-		model.BlogPost{ID: "id2", Title: "title2", Description: "descr2", Tags: "tags2", Body: "body2", Template: "template2",
-			Category: "category2", Revision: 1, CreationTimestamp: 1},
-	}
-	pageSlice := posts[:1]
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusPublished}
+	updatedPost := post
+	updatedPost.Revision = 2
 
-	repo.On("GetAll", service.pageSize+1).Return(posts, nil)
+	repo.On("Get", post.ID).Return(post, true, nil).Once()
+	repo.On("Get", post.ID).Return(updatedPost, true, nil).Once()
+	repo.On("Update", int64(1), mock.AnythingOfType("model.BlogPost")).
+		Return(model.BlogPost{}, postRepo.ErrRevisionMismatch)
 
-	response := service.GetAll()
+	response := service.Delete(editor, post.ID, 1)
 
-	if response.StatusCode != 200 {
-		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	if response.StatusCode != 409 {
+		t.Errorf("The status code was expected to be 409, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, updatedPost) {
+		t.Error("The entity was expected to be ", updatedPost, " but it was ", response.Entity)
+	}
+}
+
+// TestDeleteWithDeletedPost tests that the Delete method reports a blog post that is already in the trash as not
+// found.
+func TestDeleteWithDeletedPost(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusDeleted, DeletedTimestamp: 100}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.Delete(editor, post.ID, 0)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestRestoreWithSuccess tests that the Restore method brings a blog post back with the status it had before it was
+// deleted.
+func TestRestoreWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusDeleted, DeletedTimestamp: 100}
+	oldPost := post
+	oldPost.Revision = 1
+	oldPost.Status = model.StatusArchived
+	oldPost.DeletedTimestamp = 0
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevision", post.ID, int64(1)).Return(oldPost, true, nil)
+	repo.On("Update", int64(2), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Status == model.StatusArchived && actualPost.DeletedTimestamp == 0 && actualPost.Revision == 3
+	})).Return(post, nil)
+
+	response := service.Restore(editor, post.ID)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestRestoreWithoutPreviousRevision tests that the Restore method brings a blog post back as a draft when the
+// revision before its deletion is missing.
+func TestRestoreWithoutPreviousRevision(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusDeleted, DeletedTimestamp: 100}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevision", post.ID, int64(1)).Return(model.BlogPost{}, false, nil)
+	repo.On("Update", int64(2), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Status == model.StatusDraft
+	})).Return(post, nil)
+
+	response := service.Restore(editor, post.ID)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestRestoreWithPostNotInTrash tests that the Restore method reports a blog post that is not in the trash as not
+// found.
+func TestRestoreWithPostNotInTrash(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusPublished}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.Restore(editor, post.ID)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetWithError tests that the Get method returns the correct response when an unexpected error occurs.
+func TestGetWithError(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	id := "id"
+
+	repo.On("Get", id).Return(model.BlogPost{}, false, errors.New("unexpected error"))
+
+	response := service.Get(id)
+
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetWithNotFound tests that the Get method returns the correct response when the blog post is not found.
+func TestGetWithNotFound(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 1}
+
+	repo.On("Get", post.ID).Return(post, false, nil)
+
+	response := service.Get(post.ID)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetWithFound tests that the Get method returns the correct response when the blog post is found.
+func TestGetWithFound(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body", Template: "template",
+		Category: "category", Revision: 1}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.Get(post.ID)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, post) {
+		t.Error("The entity was expected to be ", post, " but it was ", response.Entity)
+	}
+}
+
+// TestGetWithDraft tests that the Get method doesn't return blog posts that are not published.
+func TestGetWithDraft(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusDraft}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.Get(post.ID)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetDraftWithFound tests that the GetDraft method returns blog posts that are not published.
+func TestGetDraftWithFound(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusDraft}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.GetDraft(editor, post.ID)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, post) {
+		t.Error("The entity was expected to be ", post, " but it was ", response.Entity)
+	}
+}
+
+// TestGetDraftWithOtherAuthor tests that the GetDraft method doesn't let authors read the unpublished blog posts of
+// other authors, while it lets them read their own.
+func TestGetDraftWithOtherAuthor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	author := auth.Identity{Subject: "author", Groups: []string{"author"}}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", AuthorID: "other", Revision: 1, Status: model.StatusDraft}
+	ownPost := post
+	ownPost.ID = "own"
+	ownPost.AuthorID = "author"
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("Get", ownPost.ID).Return(ownPost, true, nil)
+
+	response := service.GetDraft(author, post.ID)
+	if response.StatusCode != 403 {
+		t.Errorf("The status code was expected to be 403, but it was %d.", response.StatusCode)
+	}
+	if reflect.DeepEqual(response.Entity, post) {
+		t.Error("The blog post of the other author was not expected to be returned.")
+	}
+
+	response = service.GetDraft(author, ownPost.ID)
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetAllWithError tests that the GetAll method returns the correct response when there is an unexpected error.
+func TestGetAllWithError(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("GetAll", model.StatusPublished, postRepo.NewestFirst, service.pageSize).Return([]model.BlogPost{}, "", errors.New("unexpected error"))
+
+	response := service.GetAll(0)
+
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetAllWithInvalidPageSize tests that the GetAll method returns errors when the page size is not allowed.
+func TestGetAllWithInvalidPageSize(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	for _, pageSize := range []int64{-1, service.maxPageSize + 1} {
+		response := service.GetAll(pageSize)
+
+		if response.StatusCode != 400 {
+			t.Errorf("The status code for page size %d was expected to be 400, but it was %d.", pageSize,
+				response.StatusCode)
+		}
+		if len(response.Errors) == 0 {
+			t.Error("The response was expected to contain errors, but it didn't.")
+		}
+	}
+}
+
+// TestGetAllWithFewItems tests that the GetAll method returns the correct response when there are only a few items.
+func TestGetAllWithFewItems(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tags1"}, Body: "body1", Template: "template1",
+			Category: "category1", Revision: 1},
+		model.BlogPost{ID: "id2", Title: "title2", Description: "descr2", Tags: model.Tags{"tags2"}, Body: "body2", Template: "template2",
+			Category: "category2", Revision: 1},
+	}
+
+	repo.On("GetAll", model.StatusPublished, postRepo.NewestFirst, service.pageSize).Return(posts, "", nil)
+
+	response := service.GetAll(0)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if _, ok := response.Entity.(model.Page); !ok {
+		t.Error("The entity was expected to be of type Page, but it was ", reflect.TypeOf(response.Entity))
+	}
+
+	page, _ := response.Entity.(model.Page)
+	if !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+	}
+	if page.Cursor != "" {
+		t.Error("The cursor was expected to be empty but it was ", page.Cursor)
+	}
+	if page.HasMore {
+		t.Error("The page was expected to have no more posts, but it had.")
+	}
+}
+
+// TestGetAllWithMoreItems tests that the GetAll method returns the correct response when there are more items.
+func TestGetAllWithMoreItems(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tags1"}, Body: "body1", Template: "template1",
+			Category: "category1", Revision: 1, CreationTimestamp: 2},
+	}
+
+	repo.On("GetAll", model.StatusPublished, postRepo.NewestFirst, int64(1)).Return(posts, "cursor", nil)
+
+	response := service.GetAll(1)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
 	}
 	if _, ok := response.Entity.(model.Page); !ok {
 		t.Error("The entity was expected to be of type Page, but it was ", reflect.TypeOf(response.Entity))
 	}
 
 	page, _ := response.Entity.(model.Page)
-	if !compareSlices(page.Posts, pageSlice) {
-		t.Error("The posts were expected to be ", pageSlice, " but they were ", page.Posts)
+	if !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
 	}
-	if page.Cursor != "id1" {
-		t.Error("The cursor was expected to be id1 but it was ", page.Cursor)
+	if page.Cursor != "cursor" {
+		t.Error("The cursor was expected to be cursor but it was ", page.Cursor)
+	}
+	if !page.HasMore {
+		t.Error("The page was expected to have more posts, but it hadn't.")
 	}
 }
 
@@ -455,32 +1101,51 @@ func TestGetAllWithMoreItems(t *testing.T) {
 func TestGetMoreWithError(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	lastID := "id"
+	cursor := "cursor"
 
-	repo.On("GetMore", lastID, service.pageSize+1).Return([]model.BlogPost{}, errors.New("unexpected error"))
+	repo.On("GetMore", model.StatusPublished, cursor, postRepo.NewestFirst, service.pageSize).Return([]model.BlogPost{}, "", errors.New("unexpected error"))
 
-	response := service.GetMore(lastID)
+	response := service.GetMore(cursor, 0)
 
 	if response.StatusCode != 500 {
 		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
 	}
 }
 
+// TestGetMoreWithInvalidCursor tests that the GetMore method returns the correct response when the cursor is not
+// valid.
+func TestGetMoreWithInvalidCursor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	cursor := "cursor"
+
+	repo.On("GetMore", model.StatusPublished, cursor, postRepo.NewestFirst, service.pageSize).Return([]model.BlogPost{}, "", postRepo.ErrInvalidCursor)
+
+	response := service.GetMore(cursor, 0)
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+	if len(response.Errors) == 0 {
+		t.Error("The response was expected to contain errors, but it didn't.")
+	}
+}
+
 // TestGetMoreWithFewItems tests that the GetMore method returns the correct response when there are only a few items.
 func TestGetMoreWithFewItems(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
 	posts := []model.BlogPost{
-		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: "tags1", Body: "body1", Template: "template1",
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tags1"}, Body: "body1", Template: "template1",
 			Category: "category1", Revision: 1},
-		model.BlogPost{ID: "id2", Title: "title2", Description: "descr2", Tags: "tags2", Body: "body2", Template: "template2",
+		model.BlogPost{ID: "id2", Title: "title2", Description: "descr2", Tags: model.Tags{"tags2"}, Body: "body2", Template: "template2",
 			Category: "category2", Revision: 1},
 	}
-	lastID := "id"
+	cursor := "cursor"
 
-	repo.On("GetMore", lastID, service.pageSize+1).Return(posts, nil)
+	repo.On("GetMore", model.StatusPublished, cursor, postRepo.NewestFirst, service.pageSize).Return(posts, "", nil)
 
-	response := service.GetMore(lastID)
+	response := service.GetMore(cursor, 0)
 
 	if response.StatusCode != 200 {
 		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
@@ -496,25 +1161,24 @@ func TestGetMoreWithFewItems(t *testing.T) {
 	if page.Cursor != "" {
 		t.Error("The cursor was expected to be empty but it was ", page.Cursor)
 	}
+	if page.HasMore {
+		t.Error("The page was expected to have no more posts, but it had.")
+	}
 }
 
 // TestGetMoreWithMoreItems tests that the GetMore method returns the correct response when there are more items.
 func TestGetMoreWithMoreItems(t *testing.T) {
 	repo := new(repoMocks.Repo)
 	service := New(repo)
-	service.pageSize = 1
 	posts := []model.BlogPost{
-		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: "tags1", Body: "body1", Template: "template1",
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tags1"}, Body: "body1", Template: "template1",
 			Category: "category1", Revision: 1, CreationTimestamp: 2},
-		model.BlogPost{ID: "id2", Title: "title2", Description: "descr2", Tags: "tags2", Body: "body2", Template: "template2",
-			Category: "category2", Revision: 1, CreationTimestamp: 1},
 	}
-	pageSlice := posts[:1]
-	lastID := "id"
+	cursor := "cursor"
 
-	repo.On("GetMore", lastID, service.pageSize+1).Return(posts, nil)
+	repo.On("GetMore", model.StatusPublished, cursor, postRepo.NewestFirst, int64(1)).Return(posts, "nextCursor", nil)
 
-	response := service.GetMore(lastID)
+	response := service.GetMore(cursor, 1)
 
 	if response.StatusCode != 200 {
 		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
@@ -524,11 +1188,786 @@ func TestGetMoreWithMoreItems(t *testing.T) {
 	}
 
 	page, _ := response.Entity.(model.Page)
-	if !compareSlices(page.Posts, pageSlice) {
-		t.Error("The posts were expected to be ", pageSlice, " but they were ", page.Posts)
+	if !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+	}
+	if page.Cursor != "nextCursor" {
+		t.Error("The cursor was expected to be nextCursor but it was ", page.Cursor)
+	}
+	if !page.HasMore {
+		t.Error("The page was expected to have more posts, but it hadn't.")
+	}
+}
+
+// TestGetAllByCategoryWithSuccess tests that the GetAllByCategory method returns the correct response when the
+// operation is successful.
+func TestGetAllByCategoryWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tags1"}, Body: "body1", Template: "template1",
+			Category: "category", Revision: 1},
+	}
+
+	repo.On("GetAllByCategory", "category", model.StatusPublished, postRepo.NewestFirst, service.pageSize).Return(posts, "cursor", nil)
+
+	response := service.GetAllByCategory("category", 0)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+
+	page, _ := response.Entity.(model.Page)
+	if !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+	}
+	if page.Cursor != "cursor" || !page.HasMore {
+		t.Error("The page was expected to have more posts with cursor cursor, but it was ", page)
+	}
+}
+
+// TestGetMoreByCategoryWithError tests that the GetMoreByCategory method returns the correct response when there is
+// an unexpected error.
+func TestGetMoreByCategoryWithError(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("GetMoreByCategory", "category", model.StatusPublished, "cursor", postRepo.NewestFirst, service.pageSize).
+		Return([]model.BlogPost{}, "", errors.New("unexpected error"))
+
+	response := service.GetMoreByCategory("category", "cursor", 0)
+
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetAllByAuthorWithSuccess tests that the GetAllByAuthor and GetMoreByAuthor methods list the published blog
+// posts of an author.
+func TestGetAllByAuthorWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tags1"}, Body: "body1", Template: "template1",
+			Category: "category", AuthorID: "author", Revision: 1},
+	}
+
+	repo.On("GetAllByAuthor", "author", model.StatusPublished, postRepo.NewestFirst, service.pageSize).
+		Return(posts, "cursor", nil)
+	repo.On("GetMoreByAuthor", "author", model.StatusPublished, "cursor", postRepo.NewestFirst, int64(5)).
+		Return(posts, "", nil)
+
+	for _, response := range []globalModel.Response{service.GetAllByAuthor("author", 0),
+		service.GetMoreByAuthor("author", "cursor", 5)} {
+		if response.StatusCode != 200 {
+			t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+		}
+
+		page, _ := response.Entity.(model.Page)
+		if !compareSlices(page.Posts, posts) {
+			t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+		}
 	}
-	if page.Cursor != "id1" {
-		t.Error("The cursor was expected to be id1 but it was ", page.Cursor)
+}
+
+// TestGetAllByTagWithSuccess tests that the GetAllByTag method normalizes the tag and returns the correct response
+// when the operation is successful.
+func TestGetAllByTagWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tag"}, Body: "body1",
+			Template: "template1", Category: "category", Revision: 1},
+	}
+
+	repo.On("GetAllByTag", "tag", model.StatusPublished, postRepo.NewestFirst, service.pageSize).Return(posts, "", nil)
+
+	response := service.GetAllByTag(" Tag ", 0)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+
+	page, _ := response.Entity.(model.Page)
+	if !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+	}
+	if page.Cursor != "" || page.HasMore {
+		t.Error("The page was expected to have no more posts, but it was ", page)
+	}
+}
+
+// TestGetMoreByTagWithInvalidCursor tests that the GetMoreByTag method returns the correct response when the cursor
+// is not valid.
+func TestGetMoreByTagWithInvalidCursor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("GetMoreByTag", "tag", model.StatusPublished, "cursor", postRepo.NewestFirst, service.pageSize).
+		Return([]model.BlogPost{}, "", postRepo.ErrInvalidCursor)
+
+	response := service.GetMoreByTag("tag", "cursor", 0)
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetAllDraftsWithSuccess tests that the GetAllDrafts method lists the draft blog posts.
+func TestGetAllDraftsWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tag"}, Body: "body1",
+			Template: "template1", Category: "category", Revision: 1, Status: model.StatusDraft},
+	}
+
+	repo.On("GetAll", model.StatusDraft, postRepo.NewestFirst, service.pageSize).Return(posts, "cursor", nil)
+
+	response := service.GetAllDrafts(editor, 0)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+
+	page, _ := response.Entity.(model.Page)
+	if !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+	}
+}
+
+// TestGetAllDraftsWithAuthor tests that the GetAllDrafts method only lists the draft blog posts of the caller when
+// they are not an editor.
+func TestGetAllDraftsWithAuthor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	author := auth.Identity{Subject: "author", Groups: []string{"author"}}
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tag"}, Body: "body1",
+			Template: "template1", Category: "category", AuthorID: "author", Revision: 1, Status: model.StatusDraft},
+	}
+
+	repo.On("GetAllByAuthor", "author", model.StatusDraft, postRepo.NewestFirst, service.pageSize).
+		Return(posts, "cursor", nil)
+
+	response := service.GetAllDrafts(author, 0)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if page := response.Entity.(model.Page); !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+	}
+	repo.AssertNotCalled(t, "GetAll", model.StatusDraft, postRepo.NewestFirst, service.pageSize)
+}
+
+// TestGetMoreDraftsWithError tests that the GetMoreDrafts method returns the correct response when there is an
+// unexpected error.
+func TestGetMoreDraftsWithError(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("GetMore", model.StatusDraft, "cursor", postRepo.NewestFirst, service.pageSize).
+		Return([]model.BlogPost{}, "", errors.New("unexpected error"))
+
+	response := service.GetMoreDrafts(editor, "cursor", 0)
+
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetTagsWithError tests that the GetTags method returns the correct response when there is an unexpected error.
+func TestGetTagsWithError(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("GetTagCounts", model.StatusPublished).Return([]model.TagCount{}, errors.New("unexpected error"))
+
+	response := service.GetTags()
+
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetTagsWithSuccess tests that the GetTags method returns the correct response when the operation is
+// successful.
+func TestGetTagsWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	counts := []model.TagCount{{Tag: "go", Count: 2}, {Tag: "serverless", Count: 1}}
+
+	repo.On("GetTagCounts", model.StatusPublished).Return(counts, nil)
+
+	response := service.GetTags()
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, counts) {
+		t.Error("The tags were expected to be ", counts, " but they were ", response.Entity)
+	}
+}
+
+// TestPublishScheduledWithSuccess tests that the PublishScheduled method publishes the due drafts and skips the ones
+// that changed in the meantime.
+func TestPublishScheduledWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tag"}, Body: "body1",
+			Template: "template1", Category: "category", Revision: 1, Status: model.StatusDraft, ScheduledAt: 100},
+		model.BlogPost{ID: "id2", Title: "title2", Description: "descr", Tags: model.Tags{"tag"}, Body: "body2",
+			Template: "template2", Category: "category", Revision: 3, Status: model.StatusDraft, ScheduledAt: 200},
+	}
+
+	repo.On("GetDue", mock.AnythingOfType("int64"), service.pageSize).Return(posts, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(func(post model.BlogPost) bool {
+		return post.ID == "id1" && post.Status == model.StatusPublished && post.ScheduledAt == 0 &&
+			post.PublishedTimestamp > 0 && post.Revision == 2
+	})).Return(posts[0], nil)
+	repo.On("Update", int64(3), mock.MatchedBy(func(post model.BlogPost) bool {
+		return post.ID == "id2"
+	})).Return(model.BlogPost{}, postRepo.ErrRevisionMismatch)
+
+	response := service.PublishScheduled()
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, []string{"id1"}) {
+		t.Error("The published ids were expected to be [id1], but they were ", response.Entity)
+	}
+}
+
+// TestPublishScheduledWithError tests that the PublishScheduled method returns the correct response when the due
+// drafts cannot be fetched.
+func TestPublishScheduledWithError(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("GetDue", mock.AnythingOfType("int64"), service.pageSize).
+		Return([]model.BlogPost{}, fmt.Errorf("%w: error", postRepo.ErrThrottled))
+
+	response := service.PublishScheduled()
+
+	if response.StatusCode != 503 {
+		t.Errorf("The status code was expected to be 503, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestUpdateWithPublishingScheduledDraft tests that the Update method clears the scheduled time of a draft that is
+// published.
+func TestUpdateWithPublishingScheduledDraft(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusPublished, ScheduledAt: 100}
+	storedPost := post
+	storedPost.Status = model.StatusDraft
+
+	repo.On("Get", post.ID).Return(storedPost, true, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.ScheduledAt == 0
+	})).Return(post, nil)
+
+	response := service.Update(editor, post)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetRevisionsWithSuccess tests that the GetRevisions method returns the current revision followed by the
+// previous published ones.
+func TestGetRevisionsWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title3", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 3, Status: model.StatusPublished}
+	secondPost := post
+	secondPost.Title = "title2"
+	secondPost.Revision = 2
+	firstPost := post
+	firstPost.Title = "title1"
+	firstPost.Revision = 1
+	firstPost.Status = model.StatusDraft
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevisions", post.ID).Return([]model.BlogPost{secondPost, firstPost}, nil)
+
+	response := service.GetRevisions(post.ID)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	expectedPosts := []model.BlogPost{post, secondPost}
+	if !compareSlices(response.Entity.([]model.BlogPost), expectedPosts) {
+		t.Error("The entity was expected to be ", expectedPosts, " but it was ", response.Entity)
+	}
+}
+
+// TestGetRevisionsWithDraft tests that the GetRevisions method doesn't return the revisions of blog posts that are not
+// published.
+func TestGetRevisionsWithDraft(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusDraft}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.GetRevisions(post.ID)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetDraftRevisionsWithError tests that the GetDraftRevisions method returns the correct response when the
+// revisions cannot be fetched.
+func TestGetDraftRevisionsWithError(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusDraft}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevisions", post.ID).Return([]model.BlogPost{}, errors.New("unexpected error"))
+
+	response := service.GetDraftRevisions(editor, post.ID)
+
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetRevisionWithCurrentRevision tests that the GetRevision method returns the blog post itself for its current
+// revision.
+func TestGetRevisionWithCurrentRevision(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusPublished}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.GetRevision(post.ID, 2)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, post) {
+		t.Error("The entity was expected to be ", post, " but it was ", response.Entity)
+	}
+}
+
+// TestGetDraftRevisionsWithOtherAuthor tests that the GetDraftRevisions and the GetDraftRevision methods don't let
+// authors read the revisions of the blog posts of other authors.
+func TestGetDraftRevisionsWithOtherAuthor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	author := auth.Identity{Subject: "author", Groups: []string{"author"}}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", AuthorID: "other", Revision: 2, Status: model.StatusDraft}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.GetDraftRevisions(author, post.ID)
+	if response.StatusCode != 403 {
+		t.Errorf("The status code was expected to be 403, but it was %d.", response.StatusCode)
+	}
+	response = service.GetDraftRevision(author, post.ID, 1)
+	if response.StatusCode != 403 {
+		t.Errorf("The status code was expected to be 403, but it was %d.", response.StatusCode)
+	}
+	repo.AssertNotCalled(t, "GetRevisions", post.ID)
+	repo.AssertNotCalled(t, "GetRevision", post.ID, int64(1))
+}
+
+// TestGetRevisionWithDraftRevision tests that the GetRevision method doesn't return previous revisions that were not
+// published, while the GetDraftRevision method does.
+func TestGetRevisionWithDraftRevision(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusPublished}
+	oldPost := post
+	oldPost.Revision = 1
+	oldPost.Status = model.StatusDraft
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevision", post.ID, int64(1)).Return(oldPost, true, nil)
+
+	response := service.GetRevision(post.ID, 1)
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+
+	response = service.GetDraftRevision(editor, post.ID, 1)
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, oldPost) {
+		t.Error("The entity was expected to be ", oldPost, " but it was ", response.Entity)
+	}
+}
+
+// TestGetDraftRevisionWithNotFound tests that the GetDraftRevision method returns the correct response when the
+// revision doesn't exist.
+func TestGetDraftRevisionWithNotFound(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusPublished}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevision", post.ID, int64(5)).Return(model.BlogPost{}, false, nil)
+
+	response := service.GetDraftRevision(editor, post.ID, 5)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestRestoreRevisionWithSuccess tests that the RestoreRevision method updates the blog post with the content of the
+// old revision and keeps its status.
+func TestRestoreRevisionWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 3, Status: model.StatusPublished,
+		PublishedTimestamp: 100}
+	oldPost := post
+	oldPost.Title = "old title"
+	oldPost.Body = "old body"
+	oldPost.Revision = 1
+	oldPost.Status = model.StatusDraft
+	oldPost.PublishedTimestamp = 0
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevision", post.ID, int64(1)).Return(oldPost, true, nil)
+	repo.On("Update", int64(3), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.Title == "old title" && actualPost.Body == "old body" && actualPost.Revision == 4 &&
+			actualPost.Status == model.StatusPublished && actualPost.PublishedTimestamp == 100
+	})).Return(post, nil)
+
+	response := service.RestoreRevision(editor, post.ID, 1)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestRestoreRevisionWithNotFound tests that the RestoreRevision method returns the correct response when the
+// revision doesn't exist.
+func TestRestoreRevisionWithNotFound(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 3, Status: model.StatusPublished}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevision", post.ID, int64(1)).Return(model.BlogPost{}, false, nil)
+
+	response := service.RestoreRevision(editor, post.ID, 1)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetDiffWithSuccess tests that the GetDiff method compares an old revision with the current one.
+func TestGetDiffWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusPublished}
+	oldPost := post
+	oldPost.Title = "old title"
+	oldPost.Revision = 1
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevision", post.ID, int64(1)).Return(oldPost, true, nil)
+
+	response := service.GetDiff(post.ID, 1, 2)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	expectedDiff := model.Compare(oldPost, post)
+	if !reflect.DeepEqual(response.Entity, expectedDiff) {
+		t.Error("The entity was expected to be ", expectedDiff, " but it was ", response.Entity)
+	}
+}
+
+// TestGetDiffWithInvalidRevisions tests that the GetDiff method rejects revisions that are not positive.
+func TestGetDiffWithInvalidRevisions(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	response := service.GetDiff("id", 0, 2)
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetDraftDiffWithMissingRevision tests that the GetDraftDiff method returns the correct response when one of the
+// revisions doesn't exist.
+func TestGetDraftDiffWithMissingRevision(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2, Status: model.StatusDraft}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("GetRevision", post.ID, int64(5)).Return(model.BlogPost{}, false, nil)
+
+	response := service.GetDraftDiff(editor, post.ID, 2, 5)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestUpdateWithDeletedPost tests that the Update method reports a blog post in the trash as not found.
+func TestUpdateWithDeletedPost(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 2}
+	storedPost := post
+	storedPost.Status = model.StatusDeleted
+
+	repo.On("Get", post.ID).Return(storedPost, true, nil)
+
+	response := service.Update(editor, post)
+
+	if response.StatusCode != 404 {
+		t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestGetDraftDiffWithOtherAuthor tests that the GetDraftDiff method doesn't let authors compare the revisions of the
+// blog posts of other authors.
+func TestGetDraftDiffWithOtherAuthor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	author := auth.Identity{Subject: "author", Groups: []string{"author"}}
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", AuthorID: "other", Revision: 2, Status: model.StatusDraft}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.GetDraftDiff(author, post.ID, 1, 2)
+
+	if response.StatusCode != 403 {
+		t.Errorf("The status code was expected to be 403, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, model.Diff{}) {
+		t.Error("The entity was expected to be empty, but it was ", response.Entity)
+	}
+}
+
+// TestGetAllTrashWithSuccess tests that the GetAllTrash method lists the blog posts in the trash.
+func TestGetAllTrashWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tag"}, Body: "body1",
+			Template: "template1", Category: "category", Revision: 2, Status: model.StatusDeleted},
+	}
+
+	repo.On("GetAll", model.StatusDeleted, postRepo.NewestFirst, service.pageSize).Return(posts, "", nil)
+
+	response := service.GetAllTrash(editor, 0)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if page := response.Entity.(model.Page); !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+	}
+}
+
+// TestGetMoreTrashWithAuthor tests that the GetMoreTrash method only lists the blog posts of the caller in the trash
+// when they are not an editor.
+func TestGetMoreTrashWithAuthor(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	author := auth.Identity{Subject: "author", Groups: []string{"author"}}
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tag"}, Body: "body1",
+			Template: "template1", Category: "category", AuthorID: "author", Revision: 2, Status: model.StatusDeleted},
+	}
+
+	repo.On("GetMoreByAuthor", "author", model.StatusDeleted, "cursor", postRepo.NewestFirst, service.pageSize).
+		Return(posts, "", nil)
+
+	response := service.GetMoreTrash(author, "cursor", 0)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if page := response.Entity.(model.Page); !compareSlices(page.Posts, posts) {
+		t.Error("The posts were expected to be ", posts, " but they were ", page.Posts)
+	}
+}
+
+// TestPurgeTrashWithSuccess tests that the PurgeTrash method permanently deletes the blog posts whose retention
+// period is over.
+func TestPurgeTrashWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tag"}, Body: "body1",
+			Template: "template1", Category: "category", Revision: 2, Status: model.StatusDeleted, DeletedTimestamp: 100},
+		model.BlogPost{ID: "id2", Title: "title2", Description: "descr", Tags: model.Tags{"tag"}, Body: "body2",
+			Template: "template2", Category: "category", Revision: 2, Status: model.StatusDeleted, DeletedTimestamp: 200},
+	}
+
+	var cutoff int64
+	repo.On("GetDeleted", mock.AnythingOfType("int64"), service.pageSize).Run(func(args mock.Arguments) {
+		cutoff = args.Get(0).(int64)
+	}).Return(posts, nil)
+	repo.On("Delete", int64(2), "id1").Return(true, nil)
+	repo.On("Delete", int64(2), "id2").Return(false, postRepo.ErrRevisionMismatch)
+
+	response := service.PurgeTrash()
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, []string{"id1"}) {
+		t.Error("The purged ids were expected to be [id1], but they were ", response.Entity)
+	}
+	if expectedCutoff := time.Now().UTC().Add(-30 * 24 * time.Hour).Unix(); cutoff > expectedCutoff {
+		t.Errorf("The cutoff was expected to be at most %d, but it was %d.", expectedCutoff, cutoff)
+	}
+}
+
+// TestPurgeTrashWithError tests that the PurgeTrash method returns the correct response when a blog post cannot be
+// TestPatchWithSuccess tests that the Patch method applies the patch to the current blog post and updates it, without
+// changing the fields that cannot be patched.
+func TestPatchWithSuccess(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1, Status: model.StatusPublished, CreationTimestamp: 10,
+		PublishedTimestamp: 20}
+	patch := model.Patch{Type: model.MergePatch,
+		Document: []byte(`{"id":"other","title":"new title","revision":7,"creationTimestamp":1}`)}
+	postUpdate := post
+	postUpdate.Title = "new title"
+	postUpdate.Revision = 2
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+	repo.On("Update", int64(1), mock.MatchedBy(func(actualPost model.BlogPost) bool {
+		return actualPost.ID == "id" && actualPost.Title == "new title" && actualPost.Revision == 2 &&
+			actualPost.CreationTimestamp == 10 && actualPost.PublishedTimestamp == 20
+	})).Return(postUpdate, nil)
+
+	response := service.Patch(editor, post.ID, 1, patch)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, postUpdate) {
+		t.Error("The entity was expected to be ", postUpdate, " but it was ", response.Entity)
+	}
+}
+
+// TestPatchWithValidationErrors tests that the Patch method doesn't update a blog post that is invalid after the patch.
+func TestPatchWithValidationErrors(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Description: "descr", Tags: model.Tags{"tags"}, Body: "body",
+		Template: "template", Category: "category", Revision: 1}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.Patch(editor, post.ID, 0, model.Patch{Type: model.MergePatch, Document: []byte(`{"title":null}`)})
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+// TestPatchWithInvalidPatch tests that the Patch method returns the correct response when the patch cannot be applied.
+func TestPatchWithInvalidPatch(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Revision: 1}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.Patch(editor, post.ID, 0, model.Patch{Type: model.JSONPatch,
+		Document: []byte(`[{"op":"test","path":"/title","value":"other"}]`)})
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+	if len(response.Errors) != 1 {
+		t.Error("One error was expected, but there were ", response.Errors)
+	}
+}
+
+// TestPatchWithWrongRevision tests that the Patch method returns the current blog post with a conflict when it has a
+// different revision.
+func TestPatchWithWrongRevision(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	post := model.BlogPost{ID: "id", Title: "title", Revision: 3}
+
+	repo.On("Get", post.ID).Return(post, true, nil)
+
+	response := service.Patch(editor, post.ID, 2, model.Patch{Type: model.MergePatch, Document: []byte(`{}`)})
+
+	if response.StatusCode != 409 {
+		t.Errorf("The status code was expected to be 409, but it was %d.", response.StatusCode)
+	}
+	if !reflect.DeepEqual(response.Entity, post) {
+		t.Error("The entity was expected to be ", post, " but it was ", response.Entity)
+	}
+}
+
+// TestPatchWithMissingPost tests that the Patch method returns 404 for blog posts that don't exist or are in the trash.
+func TestPatchWithMissingPost(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+
+	repo.On("Get", "missing").Return(model.BlogPost{}, false, nil)
+	repo.On("Get", "deleted").Return(model.BlogPost{ID: "deleted", Status: model.StatusDeleted}, true, nil)
+
+	for _, id := range []string{"missing", "deleted"} {
+		response := service.Patch(editor, id, 0, model.Patch{Type: model.MergePatch, Document: []byte(`{}`)})
+
+		if response.StatusCode != 404 {
+			t.Errorf("The status code was expected to be 404, but it was %d.", response.StatusCode)
+		}
+	}
+}
+
+// purged.
+func TestPurgeTrashWithError(t *testing.T) {
+	repo := new(repoMocks.Repo)
+	service := New(repo)
+	posts := []model.BlogPost{
+		model.BlogPost{ID: "id1", Title: "title1", Description: "descr", Tags: model.Tags{"tag"}, Body: "body1",
+			Template: "template1", Category: "category", Revision: 2, Status: model.StatusDeleted, DeletedTimestamp: 100},
+	}
+
+	repo.On("GetDeleted", mock.AnythingOfType("int64"), service.pageSize).Return(posts, nil)
+	repo.On("Delete", int64(2), "id1").Return(false, fmt.Errorf("%w: error", postRepo.ErrThrottled))
+
+	response := service.PurgeTrash()
+
+	if response.StatusCode != 503 {
+		t.Errorf("The status code was expected to be 503, but it was %d.", response.StatusCode)
 	}
 }
 
@@ -545,7 +1984,7 @@ func compareSlices(arr1, arr2 []model.BlogPost) bool {
 		return false
 	}
 	for i := 0; i < len(arr1); i++ {
-		if arr1[i] != arr2[i] {
+		if !arr1[i].Equal(arr2[i]) {
 			return false
 		}
 	}
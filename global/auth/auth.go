@@ -0,0 +1,97 @@
+package auth
+
+import (
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Role represents what a caller is allowed to do. Every role includes the permissions of the roles below it: admin,
+// editor, author and reader.
+type Role string
+
+const (
+	// RoleAdmin is the role of the callers that manage the whole blog.
+	RoleAdmin Role = "admin"
+	// RoleEditor is the role of the callers that may change and publish every blog post.
+	RoleEditor Role = "editor"
+	// RoleAuthor is the role of the callers that may write blog posts and change their own ones, but not publish them.
+	RoleAuthor Role = "author"
+	// RoleReader is the role of the callers that may only read published blog posts.
+	RoleReader Role = "reader"
+)
+
+// ranks holds the position of every role in the hierarchy.
+var ranks = map[Role]int{RoleReader: 1, RoleAuthor: 2, RoleEditor: 3, RoleAdmin: 4}
+
+// Identity represents the caller of a request, as authenticated by Cognito.
+type Identity struct {
+	Subject  string
+	Username string
+	Groups   []string
+}
+
+// FromRequest returns the identity of the caller of a request from the claims that the Cognito authorizer of the API
+// Gateway adds to the request context. The second value reports if the caller is authenticated.
+func FromRequest(request events.APIGatewayProxyRequest) (Identity, bool) {
+	claims, ok := request.RequestContext.Authorizer["claims"].(map[string]interface{})
+	if !ok {
+		return Identity{}, false
+	}
+
+	identity := Identity{
+		Subject:  claimString(claims["sub"]),
+		Username: claimString(claims["cognito:username"]),
+		Groups:   parseGroups(claims["cognito:groups"]),
+	}
+
+	return identity, identity.Subject != ""
+}
+
+// Role returns the highest role of the caller among the Cognito groups. Callers that are not in any group of a role
+// are readers.
+func (identity Identity) Role() Role {
+	role := RoleReader
+	for _, group := range identity.Groups {
+		groupRole := Role(strings.ToLower(group))
+		if ranks[groupRole] > ranks[role] {
+			role = groupRole
+		}
+	}
+
+	return role
+}
+
+// HasRole checks if the caller has a role or a higher one.
+func (identity Identity) HasRole(role Role) bool {
+	return ranks[identity.Role()] >= ranks[role]
+}
+
+// claimString returns the value of a claim as a string.
+func claimString(value interface{}) string {
+	text, _ := value.(string)
+
+	return text
+}
+
+// parseGroups parses the "cognito:groups" claim. Depending on the authorizer, it's either a list or a string such as
+// "admin,editor" or "[admin editor]".
+func parseGroups(value interface{}) []string {
+	groups := []string{}
+	switch claim := value.(type) {
+	case []interface{}:
+		for _, group := range claim {
+			if text := claimString(group); text != "" {
+				groups = append(groups, text)
+			}
+		}
+	case []string:
+		groups = append(groups, claim...)
+	case string:
+		groups = strings.FieldsFunc(strings.Trim(claim, "[]"), func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+	}
+
+	return groups
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// TestFromRequest tests that the identity of the caller is read from the claims of the request context.
+func TestFromRequest(t *testing.T) {
+	testCases := []struct {
+		groups   interface{}
+		expected []string
+	}{
+		{"editor", []string{"editor"}},
+		{"author,editor", []string{"author", "editor"}},
+		{"[author editor]", []string{"author", "editor"}},
+		{[]interface{}{"author", "editor"}, []string{"author", "editor"}},
+		{nil, []string{}},
+	}
+
+	for _, testCase := range testCases {
+		request := events.APIGatewayProxyRequest{RequestContext: events.APIGatewayProxyRequestContext{
+			Authorizer: map[string]interface{}{"claims": map[string]interface{}{
+				"sub": "subject", "cognito:username": "user", "cognito:groups": testCase.groups}},
+		}}
+
+		identity, ok := FromRequest(request)
+
+		expected := Identity{Subject: "subject", Username: "user", Groups: testCase.expected}
+		if !ok || !reflect.DeepEqual(identity, expected) {
+			t.Errorf("The identity was expected to be %+v, but it was %+v.", expected, identity)
+		}
+	}
+}
+
+// TestFromRequestWithoutClaims tests that a request without claims has no identity.
+func TestFromRequestWithoutClaims(t *testing.T) {
+	if _, ok := FromRequest(events.APIGatewayProxyRequest{}); ok {
+		t.Error("The caller was expected not to be authenticated.")
+	}
+}
+
+// TestHasRole tests that every role includes the permissions of the roles below it.
+func TestHasRole(t *testing.T) {
+	testCases := []struct {
+		groups   []string
+		role     Role
+		expected bool
+	}{
+		{[]string{}, RoleReader, true},
+		{[]string{}, RoleAuthor, false},
+		{[]string{"other", "Author"}, RoleAuthor, true},
+		{[]string{"author"}, RoleEditor, false},
+		{[]string{"author", "admin"}, RoleEditor, true},
+	}
+
+	for _, testCase := range testCases {
+		identity := Identity{Subject: "subject", Groups: testCase.groups}
+
+		if identity.HasRole(testCase.role) != testCase.expected {
+			t.Errorf("HasRole(%s) of %v was expected to be %t.", testCase.role, testCase.groups, testCase.expected)
+		}
+	}
+}
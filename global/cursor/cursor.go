@@ -0,0 +1,84 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"os"
+	"strings"
+)
+
+// defaultSecret signs the cursors when the CURSOR_SECRET environment variable is not set. It's public, so cursors that
+// are signed with it can be forged, and it's only meant for tests and the local server.
+const defaultSecret = "serverless-blog-back"
+
+// ErrInvalid is returned when a cursor is malformed or its signature doesn't match.
+var ErrInvalid = errors.New("the cursor is not valid")
+
+// ErrNoSecret is returned when the CURSOR_SECRET environment variable is not set.
+var ErrNoSecret = errors.New("the CURSOR_SECRET environment variable is not set")
+
+// Codec turns pagination state into opaque, signed cursors that can be handed to clients, and back.
+type Codec struct {
+	secret []byte
+}
+
+// New returns a new codec that signs cursors with the secret in the CURSOR_SECRET environment variable, or with the
+// public default secret if it's not set. Deployed functions must call CheckSecret first.
+func New() Codec {
+	secret := os.Getenv("CURSOR_SECRET")
+	if secret == "" {
+		secret = defaultSecret
+	}
+
+	return Codec{secret: []byte(secret)}
+}
+
+// CheckSecret returns ErrNoSecret if the CURSOR_SECRET environment variable is not set, in which case the cursors are
+// signed with the public default secret.
+func CheckSecret() error {
+	if os.Getenv("CURSOR_SECRET") == "" {
+		return ErrNoSecret
+	}
+
+	return nil
+}
+
+// Encode returns a URL-safe cursor that contains the payload and its signature. An empty payload results in an
+// empty cursor.
+func (codec Codec) Encode(payload []byte) string {
+	if len(payload) == 0 {
+		return ""
+	}
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(codec.sign(payload))
+}
+
+// Decode verifies the signature of a cursor and returns its payload.
+func (codec Codec) Decode(cursor string) ([]byte, error) {
+	parts := strings.Split(cursor, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalid
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || len(payload) == 0 {
+		return nil, ErrInvalid
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, codec.sign(payload)) {
+		return nil, ErrInvalid
+	}
+
+	return payload, nil
+}
+
+// sign returns the HMAC-SHA256 signature of the payload.
+func (codec Codec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, codec.secret)
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,81 @@
+package cursor
+
+import (
+	"os"
+	"testing"
+)
+
+// TestEncodeAndDecode tests that a decoded cursor returns the encoded payload.
+func TestEncodeAndDecode(t *testing.T) {
+	codec := New()
+	payload := `{"id":{"S":"post"}}`
+
+	cursor := codec.Encode([]byte(payload))
+	decodedPayload, err := codec.Decode(cursor)
+
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if string(decodedPayload) != payload {
+		t.Error("The payload was expected to be ", payload, " but it was ", string(decodedPayload))
+	}
+}
+
+// TestNewWithSecret tests that the codec signs cursors with the secret of the environment, so that the cursors of the
+// default secret are rejected.
+func TestNewWithSecret(t *testing.T) {
+	os.Setenv("CURSOR_SECRET", "secret")
+	defer os.Unsetenv("CURSOR_SECRET")
+
+	codec := New()
+	if _, err := codec.Decode(Codec{secret: []byte(defaultSecret)}.Encode([]byte("payload"))); err != ErrInvalid {
+		t.Errorf("The error was expected to be %v, but it was %v.", ErrInvalid, err)
+	}
+	if _, err := codec.Decode(Codec{secret: []byte("secret")}.Encode([]byte("payload"))); err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+}
+
+// TestCheckSecret tests that a missing secret is reported.
+func TestCheckSecret(t *testing.T) {
+	if err := CheckSecret(); err != ErrNoSecret {
+		t.Errorf("The error was expected to be %v, but it was %v.", ErrNoSecret, err)
+	}
+
+	os.Setenv("CURSOR_SECRET", "secret")
+	defer os.Unsetenv("CURSOR_SECRET")
+
+	if err := CheckSecret(); err != nil {
+		t.Errorf("The error was expected to be %v, but it was %v.", nil, err)
+	}
+}
+
+// TestEncodeWithEmptyPayload tests that an empty payload results in an empty cursor.
+func TestEncodeWithEmptyPayload(t *testing.T) {
+	if cursor := New().Encode(nil); cursor != "" {
+		t.Error("The cursor was expected to be empty, but it was ", cursor)
+	}
+}
+
+// TestDecodeWithInvalidCursors tests that malformed or tampered cursors are rejected.
+func TestDecodeWithInvalidCursors(t *testing.T) {
+	codec := New()
+	otherCodec := Codec{secret: []byte("other")}
+	cursor := codec.Encode([]byte("payload"))
+
+	testCases := []string{
+		"",
+		"payload",
+		"cGF5bG9hZA",
+		"cGF5bG9hZA.",
+		"!!!." + cursor[len("cGF5bG9hZA."):],
+		codec.Encode([]byte("other"))[:len("b3RoZXI.")] + cursor[len("cGF5bG9hZA."):],
+		otherCodec.Encode([]byte("payload")),
+	}
+
+	for _, testCase := range testCases {
+		if _, err := codec.Decode(testCase); err != ErrInvalid {
+			t.Error("The cursor ", testCase, " was expected to be invalid, but the error was ", err)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines that are shown around each change.
+const context = 3
+
+// operation represents a line that is kept, removed or added when turning one text into another.
+type operation struct {
+	kind byte
+	line string
+}
+
+// Unified returns the line-level differences between two texts in the unified diff format. The names label the two
+// texts in the header. If the texts are the same, it returns an empty string.
+func Unified(fromName string, toName string, from string, to string) string {
+	if from == to {
+		return ""
+	}
+
+	operations := compare(splitLines(from), splitLines(to))
+
+	var builder strings.Builder
+	builder.WriteString("--- " + fromName + "\n")
+	builder.WriteString("+++ " + toName + "\n")
+
+	// fromLines and toLines hold the number of lines of each text that come before every operation.
+	fromLines := make([]int, len(operations)+1)
+	toLines := make([]int, len(operations)+1)
+	for i, op := range operations {
+		fromLines[i+1], toLines[i+1] = fromLines[i], toLines[i]
+		if op.kind != '+' {
+			fromLines[i+1]++
+		}
+		if op.kind != '-' {
+			toLines[i+1]++
+		}
+	}
+
+	for start := 0; start < len(operations); {
+		first := nextChange(operations, start)
+		if first == len(operations) {
+			break
+		}
+
+		// The hunk grows while the next change is close enough for the context lines to overlap.
+		last := first
+		for next := nextChange(operations, last+1); next < len(operations) && next-last-1 <= 2*context; {
+			last = next
+			next = nextChange(operations, last+1)
+		}
+
+		hunkStart := max(first-context, 0)
+		hunkEnd := min(last+context+1, len(operations))
+		builder.WriteString(fmt.Sprintf("@@ -%s +%s @@\n",
+			hunkRange(fromLines[hunkStart], fromLines[hunkEnd]-fromLines[hunkStart]),
+			hunkRange(toLines[hunkStart], toLines[hunkEnd]-toLines[hunkStart])))
+		for _, op := range operations[hunkStart:hunkEnd] {
+			builder.WriteString(string(op.kind) + op.line + "\n")
+		}
+
+		start = hunkEnd
+	}
+
+	return builder.String()
+}
+
+// compare returns the operations that turn the first list of lines into the second one, based on their longest
+// common subsequence.
+func compare(from []string, to []string) []operation {
+	// lengths[i][j] is the length of the longest common subsequence of from[i:] and to[j:].
+	lengths := make([][]int, len(from)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+
+	operations := make([]operation, 0, len(from)+len(to))
+	i, j := 0, 0
+	for i < len(from) && j < len(to) {
+		switch {
+		case from[i] == to[j]:
+			operations = append(operations, operation{kind: ' ', line: from[i]})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			operations = append(operations, operation{kind: '-', line: from[i]})
+			i++
+		default:
+			operations = append(operations, operation{kind: '+', line: to[j]})
+			j++
+		}
+	}
+	for ; i < len(from); i++ {
+		operations = append(operations, operation{kind: '-', line: from[i]})
+	}
+	for ; j < len(to); j++ {
+		operations = append(operations, operation{kind: '+', line: to[j]})
+	}
+
+	return operations
+}
+
+// nextChange returns the index of the first operation from start onwards that is not an unchanged line, or the
+// number of operations if there is none.
+func nextChange(operations []operation, start int) int {
+	for i := start; i < len(operations); i++ {
+		if operations[i].kind != ' ' {
+			return i
+		}
+	}
+
+	return len(operations)
+}
+
+// hunkRange formats the range of a hunk for one of the texts. The line before the hunk is used as the start of an
+// empty range.
+func hunkRange(linesBefore int, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", linesBefore)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", linesBefore+1)
+	}
+
+	return fmt.Sprintf("%d,%d", linesBefore+1, count)
+}
+
+// splitLines splits a text into lines. A final line break doesn't start a new line.
+func splitLines(text string) []string {
+	if text == "" {
+		return []string{}
+	}
+
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func min(a int, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,54 @@
+package diff
+
+import (
+	"testing"
+)
+
+// TestUnifiedWithSameTexts tests that there are no differences between the same texts.
+func TestUnifiedWithSameTexts(t *testing.T) {
+	if result := Unified("a", "b", "line1\nline2", "line1\nline2"); result != "" {
+		t.Error("The diff was expected to be empty, but it was ", result)
+	}
+}
+
+// TestUnifiedWithChangedLine tests that a changed line is shown as removed and added, with its context.
+func TestUnifiedWithChangedLine(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n"
+	to := "1\n2\n3\n4\nfive\n6\n7\n8\n9\n"
+	expected := "--- a\n+++ b\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n"
+
+	if result := Unified("a", "b", from, to); result != expected {
+		t.Errorf("The diff was expected to be:\n%s\nbut it was:\n%s", expected, result)
+	}
+}
+
+// TestUnifiedWithDistantChanges tests that changes that are far from each other are shown in separate hunks.
+func TestUnifiedWithDistantChanges(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10"
+	to := "0\n1\n2\n3\n4\n5\n6\n7\n8\n9"
+	expected := "--- a\n+++ b\n@@ -1,3 +1,4 @@\n+0\n 1\n 2\n 3\n@@ -7,4 +8,3 @@\n 7\n 8\n 9\n-10\n"
+
+	if result := Unified("a", "b", from, to); result != expected {
+		t.Errorf("The diff was expected to be:\n%s\nbut it was:\n%s", expected, result)
+	}
+}
+
+// TestUnifiedWithCloseChanges tests that changes whose context overlaps are shown in the same hunk.
+func TestUnifiedWithCloseChanges(t *testing.T) {
+	from := "1\n2\n3\n4\n5\n6\n7\n8"
+	to := "one\n2\n3\n4\n5\n6\n7\neight"
+	expected := "--- a\n+++ b\n@@ -1,8 +1,8 @@\n-1\n+one\n 2\n 3\n 4\n 5\n 6\n 7\n-8\n+eight\n"
+
+	if result := Unified("a", "b", from, to); result != expected {
+		t.Errorf("The diff was expected to be:\n%s\nbut it was:\n%s", expected, result)
+	}
+}
+
+// TestUnifiedWithEmptyText tests that every line is added when the first text is empty.
+func TestUnifiedWithEmptyText(t *testing.T) {
+	expected := "--- a\n+++ b\n@@ -0,0 +1,2 @@\n+1\n+2\n"
+
+	if result := Unified("a", "b", "", "1\n2"); result != expected {
+		t.Errorf("The diff was expected to be:\n%s\nbut it was:\n%s", expected, result)
+	}
+}
@@ -0,0 +1,142 @@
+package httpadapter
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/model"
+	"github.com/printezisn/serverless-blog-back/global/router"
+	"github.com/printezisn/serverless-blog-back/global/writer"
+)
+
+// Handler serves an API Gateway handler over net/http, so that the API can run locally without SAM and Docker.
+type Handler struct {
+	handler router.Handler
+}
+
+// New creates a new net/http handler for an API Gateway handler.
+func New(handler router.Handler) Handler {
+	return Handler{handler: handler}
+}
+
+// ServeHTTP converts the HTTP request to an API Gateway request, handles it and writes the API Gateway response back.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	request, err := ToRequest(r)
+	if err != nil {
+		writeError(w, writer.Error(400, model.NewError(model.CodeInvalidInput, "The request body could not be read.")))
+		return
+	}
+
+	response, err := h.handler(request)
+	if err != nil {
+		log.Println("An error occurred while handling a request: ", err)
+		writeError(w, writer.Error(500))
+		return
+	}
+
+	if err = WriteResponse(w, response); err != nil {
+		log.Println("An error occurred while writing a response: ", err)
+	}
+}
+
+// ToRequest converts an HTTP request to an API Gateway proxy request. The headers and the query string are copied
+// both as single values, keeping the last one like the API Gateway does, and as multiple values. Bodies that are not
+// valid UTF-8 are base64-encoded. The path parameters are left empty, since the router fills them from the route that
+// matches the path.
+func ToRequest(r *http.Request) (events.APIGatewayProxyRequest, error) {
+	request := events.APIGatewayProxyRequest{
+		Resource:                        r.URL.Path,
+		Path:                            r.URL.Path,
+		HTTPMethod:                      r.Method,
+		Headers:                         map[string]string{},
+		MultiValueHeaders:               map[string][]string{},
+		QueryStringParameters:           map[string]string{},
+		MultiValueQueryStringParameters: map[string][]string{},
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Path:       r.URL.Path,
+			HTTPMethod: r.Method,
+			Identity:   events.APIGatewayRequestIdentity{SourceIP: sourceIP(r.RemoteAddr)},
+		},
+	}
+
+	for name, values := range r.Header {
+		request.Headers[name] = values[len(values)-1]
+		request.MultiValueHeaders[name] = values
+	}
+	if r.Host != "" {
+		request.Headers["Host"] = r.Host
+		request.MultiValueHeaders["Host"] = []string{r.Host}
+	}
+	for name, values := range r.URL.Query() {
+		request.QueryStringParameters[name] = values[len(values)-1]
+		request.MultiValueQueryStringParameters[name] = values
+	}
+
+	if r.Body == nil {
+		return request, nil
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return request, err
+	}
+	if utf8.Valid(body) {
+		request.Body = string(body)
+	} else {
+		request.Body = base64.StdEncoding.EncodeToString(body)
+		request.IsBase64Encoded = true
+	}
+
+	return request, nil
+}
+
+// WriteResponse writes an API Gateway proxy response to an HTTP response. Base64-encoded bodies are decoded first.
+func WriteResponse(w http.ResponseWriter, response events.APIGatewayProxyResponse) error {
+	body := []byte(response.Body)
+	if response.IsBase64Encoded {
+		decoded, err := base64.StdEncoding.DecodeString(response.Body)
+		if err != nil {
+			writeError(w, writer.Error(500))
+			return err
+		}
+		body = decoded
+	}
+
+	for name, values := range response.MultiValueHeaders {
+		for _, value := range values {
+			w.Header().Add(name, value)
+		}
+	}
+	for name, value := range response.Headers {
+		w.Header().Set(name, value)
+	}
+
+	statusCode := response.StatusCode
+	if statusCode == 0 {
+		statusCode = http.StatusOK
+	}
+	w.WriteHeader(statusCode)
+	_, err := w.Write(body)
+
+	return err
+}
+
+// writeError writes an error response, which is never base64-encoded.
+func writeError(w http.ResponseWriter, response events.APIGatewayProxyResponse) {
+	if err := WriteResponse(w, response); err != nil {
+		log.Println("An error occurred while writing a response: ", err)
+	}
+}
+
+// sourceIP returns the IP address of a remote address such as "127.0.0.1:54321".
+func sourceIP(remoteAddr string) string {
+	if i := strings.LastIndex(remoteAddr, ":"); i >= 0 {
+		return strings.Trim(remoteAddr[:i], "[]")
+	}
+
+	return remoteAddr
+}
@@ -0,0 +1,74 @@
+package httpadapter
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// TestToRequest tests that the method, the path, the query string, the headers and the body are copied.
+func TestToRequest(t *testing.T) {
+	r := httptest.NewRequest("POST", "/posts/id?tag=a&tag=b&pageSize=5", strings.NewReader(`{"title":"title"}`))
+	r.Header.Add("Content-Type", "application/json")
+	r.Header.Add("X-Value", "first")
+	r.Header.Add("X-Value", "second")
+
+	request, err := ToRequest(r)
+
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if request.HTTPMethod != "POST" || request.Path != "/posts/id" {
+		t.Errorf("The request was expected to be POST /posts/id, but it was %s %s.", request.HTTPMethod, request.Path)
+	}
+	if request.QueryStringParameters["tag"] != "b" || len(request.MultiValueQueryStringParameters["tag"]) != 2 ||
+		request.QueryStringParameters["pageSize"] != "5" {
+		t.Error("The query string was not copied correctly: ", request.MultiValueQueryStringParameters)
+	}
+	if request.Headers["Content-Type"] != "application/json" || request.Headers["X-Value"] != "second" ||
+		len(request.MultiValueHeaders["X-Value"]) != 2 {
+		t.Error("The headers were not copied correctly: ", request.MultiValueHeaders)
+	}
+	if request.Body != `{"title":"title"}` || request.IsBase64Encoded {
+		t.Error("The body was expected to be copied as text, but it was ", request.Body)
+	}
+}
+
+// TestToRequestWithBinaryBody tests that a body that is not valid UTF-8 is base64-encoded.
+func TestToRequestWithBinaryBody(t *testing.T) {
+	body := []byte{0xff, 0xfe, 0x00}
+	request, _ := ToRequest(httptest.NewRequest("PUT", "/posts", strings.NewReader(string(body))))
+
+	if !request.IsBase64Encoded || request.Body != base64.StdEncoding.EncodeToString(body) {
+		t.Error("The body was expected to be base64-encoded, but it was ", request.Body)
+	}
+}
+
+// TestServeHTTP tests that the response of the handler is written back, including its headers and base64 body.
+func TestServeHTTP(t *testing.T) {
+	handler := New(func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+			StatusCode:        201,
+			Headers:           map[string]string{"Content-Type": "application/json"},
+			MultiValueHeaders: map[string][]string{"Vary": {"Origin", "Accept"}},
+			Body:              base64.StdEncoding.EncodeToString([]byte(request.Path)),
+			IsBase64Encoded:   true,
+		}, nil
+	})
+	w := httptest.NewRecorder()
+
+	handler.ServeHTTP(w, httptest.NewRequest("GET", "/posts", nil))
+
+	if w.Code != 201 {
+		t.Errorf("The status code was expected to be 201, but it was %d.", w.Code)
+	}
+	if w.Header().Get("Content-Type") != "application/json" || len(w.Header()["Vary"]) != 2 {
+		t.Error("The headers were not written correctly: ", w.Header())
+	}
+	if w.Body.String() != "/posts" {
+		t.Error("The body was expected to be /posts, but it was ", w.Body.String())
+	}
+}
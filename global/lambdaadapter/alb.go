@@ -0,0 +1,117 @@
+package lambdaadapter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/router"
+)
+
+// ALB returns a Lambda handler for the events of an Application Load Balancer target group. It works whether the
+// target group has multi-value headers enabled or not.
+func ALB(handler router.Handler) func(events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+	return func(request events.ALBTargetGroupRequest) (events.ALBTargetGroupResponse, error) {
+		response, err := handler(FromALBRequest(request))
+
+		return ToALBResponse(response, request.MultiValueHeaders != nil), err
+	}
+}
+
+// FromALBRequest converts the request of a load balancer to the normalized request. The load balancer passes the
+// query string as it was sent, so its names and values are unescaped. It base64-encodes some bodies, which are kept
+// as they are, since the router decodes them. There is no authorizer, so the callers are never authenticated.
+func FromALBRequest(request events.ALBTargetGroupRequest) events.APIGatewayProxyRequest {
+	normalized := events.APIGatewayProxyRequest{
+		Resource:                        request.Path,
+		Path:                            request.Path,
+		HTTPMethod:                      request.HTTPMethod,
+		Headers:                         map[string]string{},
+		MultiValueHeaders:               map[string][]string{},
+		QueryStringParameters:           map[string]string{},
+		MultiValueQueryStringParameters: map[string][]string{},
+		Body:                            request.Body,
+		IsBase64Encoded:                 request.IsBase64Encoded,
+		RequestContext: events.APIGatewayProxyRequestContext{
+			Path:       request.Path,
+			HTTPMethod: request.HTTPMethod,
+		},
+	}
+
+	for name, value := range request.Headers {
+		normalized.Headers[name] = value
+		normalized.MultiValueHeaders[name] = []string{value}
+	}
+	for name, values := range request.MultiValueHeaders {
+		if len(values) > 0 {
+			normalized.Headers[name] = values[len(values)-1]
+			normalized.MultiValueHeaders[name] = values
+		}
+	}
+
+	query := map[string][]string{}
+	for name, value := range request.QueryStringParameters {
+		query[name] = []string{value}
+	}
+	for name, values := range request.MultiValueQueryStringParameters {
+		query[name] = values
+	}
+	for name, values := range query {
+		name = unescape(name)
+		for _, value := range values {
+			normalized.MultiValueQueryStringParameters[name] = append(normalized.MultiValueQueryStringParameters[name],
+				unescape(value))
+		}
+		if count := len(normalized.MultiValueQueryStringParameters[name]); count > 0 {
+			normalized.QueryStringParameters[name] = normalized.MultiValueQueryStringParameters[name][count-1]
+		}
+	}
+
+	return normalized
+}
+
+// ToALBResponse converts the normalized response to the response of a load balancer. A target group with multi-value
+// headers only reads the multi-value headers of the response, and one without them only reads the single ones.
+func ToALBResponse(response events.APIGatewayProxyResponse, multiValue bool) events.ALBTargetGroupResponse {
+	result := events.ALBTargetGroupResponse{
+		StatusCode:        response.StatusCode,
+		StatusDescription: fmt.Sprintf("%d %s", response.StatusCode, http.StatusText(response.StatusCode)),
+		Body:              response.Body,
+		IsBase64Encoded:   response.IsBase64Encoded,
+	}
+
+	if multiValue {
+		result.MultiValueHeaders = map[string][]string{}
+		for name, values := range response.MultiValueHeaders {
+			result.MultiValueHeaders[name] = append(result.MultiValueHeaders[name], values...)
+		}
+		for name, value := range response.Headers {
+			result.MultiValueHeaders[name] = append(result.MultiValueHeaders[name], value)
+		}
+
+		return result
+	}
+
+	result.Headers = map[string]string{}
+	for name, values := range response.MultiValueHeaders {
+		if len(values) > 0 {
+			result.Headers[name] = values[len(values)-1]
+		}
+	}
+	for name, value := range response.Headers {
+		result.Headers[name] = value
+	}
+
+	return result
+}
+
+// unescape unescapes a part of a query string, or returns it as it is if it's not escaped properly.
+func unescape(value string) string {
+	unescaped, err := url.QueryUnescape(value)
+	if err != nil {
+		return value
+	}
+
+	return unescaped
+}
@@ -0,0 +1,127 @@
+// Package lambdaadapter lets the API handle the events of other Lambda triggers than the REST API of the API
+// Gateway. The router, the middlewares and the handlers work on a single normalized request and response, which are
+// the REST API proxy request and response, so every event is converted to them and back.
+package lambdaadapter
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/router"
+)
+
+// HTTPAPI returns a Lambda handler for the events of an API Gateway HTTP API with the payload format 2.0.
+func HTTPAPI(handler router.Handler) func(events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+	return func(request events.APIGatewayV2HTTPRequest) (events.APIGatewayV2HTTPResponse, error) {
+		response, err := handler(FromHTTPAPIRequest(request))
+
+		return ToHTTPAPIResponse(response), err
+	}
+}
+
+// FromHTTPAPIRequest converts the request of an HTTP API to the normalized request. The cookies become the "Cookie"
+// header and the claims of a JWT authorizer are added to the request context the way the Cognito authorizer of a REST
+// API adds them. A base64-encoded body is kept as it is, since the router decodes it.
+func FromHTTPAPIRequest(request events.APIGatewayV2HTTPRequest) events.APIGatewayProxyRequest {
+	path := request.RawPath
+	if path == "" {
+		path = request.RequestContext.HTTP.Path
+	}
+
+	normalized := events.APIGatewayProxyRequest{
+		Resource:                        path,
+		Path:                            path,
+		HTTPMethod:                      request.RequestContext.HTTP.Method,
+		Headers:                         map[string]string{},
+		MultiValueHeaders:               map[string][]string{},
+		QueryStringParameters:           map[string]string{},
+		MultiValueQueryStringParameters: map[string][]string{},
+		StageVariables:                  request.StageVariables,
+		Body:                            request.Body,
+		IsBase64Encoded:                 request.IsBase64Encoded,
+		RequestContext: events.APIGatewayProxyRequestContext{
+			AccountID:  request.RequestContext.AccountID,
+			APIID:      request.RequestContext.APIID,
+			DomainName: request.RequestContext.DomainName,
+			Stage:      request.RequestContext.Stage,
+			RequestID:  request.RequestContext.RequestID,
+			Path:       path,
+			HTTPMethod: request.RequestContext.HTTP.Method,
+			Identity: events.APIGatewayRequestIdentity{
+				SourceIP:  request.RequestContext.HTTP.SourceIP,
+				UserAgent: request.RequestContext.HTTP.UserAgent,
+			},
+		},
+	}
+
+	// Headers with multiple values are joined with commas in the payload format 2.0.
+	for name, value := range request.Headers {
+		normalized.Headers[name] = value
+		normalized.MultiValueHeaders[name] = splitValues(value)
+	}
+	if len(request.Cookies) > 0 {
+		normalized.Headers["Cookie"] = strings.Join(request.Cookies, "; ")
+		normalized.MultiValueHeaders["Cookie"] = []string{normalized.Headers["Cookie"]}
+	}
+
+	query, err := url.ParseQuery(request.RawQueryString)
+	if err != nil {
+		query = url.Values{}
+		for name, value := range request.QueryStringParameters {
+			query[name] = splitValues(value)
+		}
+	}
+	for name, values := range query {
+		normalized.QueryStringParameters[name] = values[len(values)-1]
+		normalized.MultiValueQueryStringParameters[name] = values
+	}
+
+	if authorizer := request.RequestContext.Authorizer; authorizer != nil && authorizer.JWT != nil {
+		claims := make(map[string]interface{}, len(authorizer.JWT.Claims))
+		for name, value := range authorizer.JWT.Claims {
+			claims[name] = value
+		}
+		normalized.RequestContext.Authorizer = map[string]interface{}{"claims": claims}
+	}
+
+	return normalized
+}
+
+// ToHTTPAPIResponse converts the normalized response to the response of an HTTP API. Headers with multiple values are
+// joined with commas, except for "Set-Cookie", whose values become the cookies of the response.
+func ToHTTPAPIResponse(response events.APIGatewayProxyResponse) events.APIGatewayV2HTTPResponse {
+	result := events.APIGatewayV2HTTPResponse{
+		StatusCode:      response.StatusCode,
+		Headers:         map[string]string{},
+		Body:            response.Body,
+		IsBase64Encoded: response.IsBase64Encoded,
+	}
+
+	for name, values := range response.MultiValueHeaders {
+		if strings.EqualFold(name, "Set-Cookie") {
+			result.Cookies = append(result.Cookies, values...)
+			continue
+		}
+		result.Headers[name] = strings.Join(values, ",")
+	}
+	for name, value := range response.Headers {
+		if strings.EqualFold(name, "Set-Cookie") {
+			result.Cookies = append(result.Cookies, value)
+			continue
+		}
+		result.Headers[name] = value
+	}
+
+	return result
+}
+
+// splitValues splits the comma-separated values of a header or a query string parameter.
+func splitValues(value string) []string {
+	values := strings.Split(value, ",")
+	for i := range values {
+		values[i] = strings.TrimSpace(values[i])
+	}
+
+	return values
+}
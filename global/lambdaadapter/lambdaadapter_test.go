@@ -0,0 +1,116 @@
+package lambdaadapter
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+)
+
+// echo is a handler that returns the path and the query string of the request, and sets a cookie.
+func echo(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{
+		StatusCode:        200,
+		Headers:           map[string]string{"Content-Type": "application/json"},
+		MultiValueHeaders: map[string][]string{"Set-Cookie": {"a=1", "b=2"}},
+		Body:              request.HTTPMethod + " " + request.Path + " " + request.QueryStringParameters["tag"],
+	}, nil
+}
+
+// TestFromHTTPAPIRequest tests that the request of an HTTP API is normalized, including the JWT claims.
+func TestFromHTTPAPIRequest(t *testing.T) {
+	request := events.APIGatewayV2HTTPRequest{
+		RawPath:        "/posts",
+		RawQueryString: "tag=a%20b&tag=c&pageSize=5",
+		Cookies:        []string{"a=1", "b=2"},
+		Headers:        map[string]string{"accept": "text/html, application/json"},
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			RequestID: "id",
+			HTTP:      events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: "GET"},
+			Authorizer: &events.APIGatewayV2HTTPRequestContextAuthorizerDescription{
+				JWT: &events.APIGatewayV2HTTPRequestContextAuthorizerJWTDescription{
+					Claims: map[string]string{"sub": "user", "cognito:groups": "[author editor]"},
+				},
+			},
+		},
+	}
+
+	normalized := FromHTTPAPIRequest(request)
+
+	if normalized.HTTPMethod != "GET" || normalized.Path != "/posts" || normalized.RequestContext.RequestID != "id" {
+		t.Errorf("The request was expected to be GET /posts, but it was %s %s.", normalized.HTTPMethod, normalized.Path)
+	}
+	if !reflect.DeepEqual(normalized.MultiValueQueryStringParameters["tag"], []string{"a b", "c"}) ||
+		normalized.QueryStringParameters["pageSize"] != "5" {
+		t.Error("The query string was not normalized correctly: ", normalized.MultiValueQueryStringParameters)
+	}
+	if !reflect.DeepEqual(normalized.MultiValueHeaders["accept"], []string{"text/html", "application/json"}) ||
+		normalized.Headers["Cookie"] != "a=1; b=2" {
+		t.Error("The headers were not normalized correctly: ", normalized.MultiValueHeaders)
+	}
+	if identity, ok := auth.FromRequest(normalized); !ok || !identity.HasRole(auth.RoleEditor) {
+		t.Errorf("The caller was expected to be an editor, but it was %+v.", identity)
+	}
+}
+
+// TestFromRequestWithBase64Body tests that the base64-encoded bodies of an HTTP API and a load balancer are kept
+// encoded for the router, together with their flag.
+func TestFromRequestWithBase64Body(t *testing.T) {
+	encoded := base64.StdEncoding.EncodeToString([]byte(`{"title":"title"}`))
+
+	normalizedRequests := []events.APIGatewayProxyRequest{
+		FromHTTPAPIRequest(events.APIGatewayV2HTTPRequest{Body: encoded, IsBase64Encoded: true}),
+		FromALBRequest(events.ALBTargetGroupRequest{Body: encoded, IsBase64Encoded: true}),
+	}
+
+	for _, normalized := range normalizedRequests {
+		if normalized.Body != encoded || !normalized.IsBase64Encoded {
+			t.Errorf("The body was expected to be %s and base64-encoded, but it was %s.", encoded, normalized.Body)
+		}
+	}
+}
+
+// TestHTTPAPI tests that the response is converted back, with the cookies taken out of the headers.
+func TestHTTPAPI(t *testing.T) {
+	response, _ := HTTPAPI(echo)(events.APIGatewayV2HTTPRequest{RawPath: "/posts", RawQueryString: "tag=a",
+		RequestContext: events.APIGatewayV2HTTPRequestContext{
+			HTTP: events.APIGatewayV2HTTPRequestContextHTTPDescription{Method: "GET"},
+		}})
+
+	if response.StatusCode != 200 || response.Body != "GET /posts a" {
+		t.Errorf("The response was expected to be 200 with GET /posts a, but it was %d with %s.", response.StatusCode,
+			response.Body)
+	}
+	if !reflect.DeepEqual(response.Cookies, []string{"a=1", "b=2"}) || response.Headers["Set-Cookie"] != "" ||
+		response.Headers["Content-Type"] != "application/json" {
+		t.Errorf("The headers were not converted correctly: %v and %v.", response.Headers, response.Cookies)
+	}
+}
+
+// TestALB tests that the requests of a load balancer are normalized and answered with or without multi-value headers.
+func TestALB(t *testing.T) {
+	requests := []events.ALBTargetGroupRequest{
+		{HTTPMethod: "GET", Path: "/posts", QueryStringParameters: map[string]string{"tag": "a%20b"},
+			Headers: map[string]string{"accept": "application/json"}},
+		{HTTPMethod: "GET", Path: "/posts", MultiValueQueryStringParameters: map[string][]string{"tag": {"c", "a+b"}},
+			MultiValueHeaders: map[string][]string{"accept": {"application/json"}}},
+	}
+
+	for _, request := range requests {
+		response, _ := ALB(echo)(request)
+
+		if response.StatusCode != 200 || response.StatusDescription != "200 OK" || response.Body != "GET /posts a b" {
+			t.Errorf("The response was expected to be 200 with GET /posts a b, but it was %s with %s.",
+				response.StatusDescription, response.Body)
+		}
+		if request.MultiValueHeaders != nil {
+			if len(response.MultiValueHeaders["Set-Cookie"]) != 2 || response.Headers != nil {
+				t.Error("The response was expected to have multi-value headers, but it had ", response.Headers)
+			}
+		} else if response.Headers["Set-Cookie"] != "b=2" || response.MultiValueHeaders != nil {
+			t.Error("The response was expected to have single headers, but it had ", response.MultiValueHeaders)
+		}
+	}
+}
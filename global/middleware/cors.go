@@ -0,0 +1,137 @@
+package middleware
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/router"
+)
+
+// CORSPolicy represents which cross-origin requests are allowed and what the browser may do with their responses.
+type CORSPolicy struct {
+	// AllowedOrigins are the origins that may send requests. A "*" allows every origin.
+	AllowedOrigins []string
+	// AllowedMethods are the methods that the origins may use.
+	AllowedMethods []string
+	// AllowedHeaders are the request headers that the origins may send.
+	AllowedHeaders []string
+	// ExposedHeaders are the response headers that the origins may read.
+	ExposedHeaders []string
+	// MaxAge is the number of seconds that the answer to a preflight request may be cached. If it's 0, it's not sent.
+	MaxAge int64
+	// AllowCredentials allows the origins to send credentials, such as cookies.
+	AllowCredentials bool
+}
+
+// NewCORSPolicy creates a CORS policy from the environment variables CORS_ALLOWED_ORIGINS, CORS_ALLOWED_METHODS,
+// CORS_ALLOWED_HEADERS and CORS_EXPOSED_HEADERS, which are comma-separated lists, CORS_MAX_AGE and
+// CORS_ALLOW_CREDENTIALS. Variables that are not set keep the default policy, which allows every origin.
+func NewCORSPolicy() CORSPolicy {
+	policy := CORSPolicy{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"DELETE", "GET", "HEAD", "OPTIONS", "PATCH", "POST", "PUT"},
+		AllowedHeaders: []string{"Content-Type", "X-Amz-Date", "Authorization", "X-Api-Key", "X-Amz-Security-Token",
+			"If-Match", "If-None-Match", "X-Request-Id"},
+		ExposedHeaders: []string{"ETag", "X-Request-Id"},
+	}
+
+	if value, ok := os.LookupEnv("CORS_ALLOWED_ORIGINS"); ok {
+		policy.AllowedOrigins = splitList(value)
+	}
+	if value, ok := os.LookupEnv("CORS_ALLOWED_METHODS"); ok {
+		policy.AllowedMethods = splitList(value)
+	}
+	if value, ok := os.LookupEnv("CORS_ALLOWED_HEADERS"); ok {
+		policy.AllowedHeaders = splitList(value)
+	}
+	if value, ok := os.LookupEnv("CORS_EXPOSED_HEADERS"); ok {
+		policy.ExposedHeaders = splitList(value)
+	}
+	if maxAge, err := strconv.ParseInt(os.Getenv("CORS_MAX_AGE"), 10, 64); err == nil && maxAge > 0 {
+		policy.MaxAge = maxAge
+	}
+	if allowCredentials, err := strconv.ParseBool(os.Getenv("CORS_ALLOW_CREDENTIALS")); err == nil {
+		policy.AllowCredentials = allowCredentials
+	}
+
+	return policy
+}
+
+// CORS returns a middleware that adds the CORS headers of a policy to every response. The origin of the request is
+// only echoed if the policy allows it, and requests without an origin only get a wildcard. The answers to preflight
+// requests also carry the maximum age of the policy.
+func CORS(policy CORSPolicy) router.Middleware {
+	return func(handler router.Handler) router.Handler {
+		return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			response, err := handler(request)
+
+			if origin := policy.allowedOrigin(Header(request, "Origin")); origin != "" {
+				setDefaultHeader(&response, "Access-Control-Allow-Origin", origin)
+				if policy.AllowCredentials {
+					setDefaultHeader(&response, "Access-Control-Allow-Credentials", "true")
+				}
+			}
+			addVary(&response, "Origin")
+			setDefaultHeader(&response, "Access-Control-Allow-Methods", strings.Join(policy.AllowedMethods, ","))
+			setDefaultHeader(&response, "Access-Control-Allow-Headers", strings.Join(policy.AllowedHeaders, ","))
+			if len(policy.ExposedHeaders) > 0 {
+				setDefaultHeader(&response, "Access-Control-Expose-Headers", strings.Join(policy.ExposedHeaders, ","))
+			}
+			if strings.EqualFold(request.HTTPMethod, "OPTIONS") && policy.MaxAge > 0 {
+				setDefaultHeader(&response, "Access-Control-Max-Age", strconv.FormatInt(policy.MaxAge, 10))
+			}
+
+			return response, err
+		}
+	}
+}
+
+// allowedOrigin returns the value of the "Access-Control-Allow-Origin" header for the origin of a request, or an empty
+// string if the origin is not allowed. A wildcard policy echoes the origin when credentials are allowed, since
+// browsers don't accept a wildcard together with credentials.
+func (policy CORSPolicy) allowedOrigin(origin string) string {
+	for _, allowed := range policy.AllowedOrigins {
+		if allowed == "*" {
+			if origin != "" && policy.AllowCredentials {
+				return origin
+			}
+
+			return "*"
+		}
+		if origin != "" && strings.EqualFold(allowed, origin) {
+			return origin
+		}
+	}
+
+	return ""
+}
+
+// addVary adds a header to the "Vary" header of a response, unless it's already there.
+func addVary(response *events.APIGatewayProxyResponse, name string) {
+	vary := response.Headers["Vary"]
+	for _, value := range strings.Split(vary, ",") {
+		if strings.EqualFold(strings.TrimSpace(value), name) {
+			return
+		}
+	}
+
+	if vary == "" {
+		setDefaultHeader(response, "Vary", name)
+	} else {
+		response.Headers["Vary"] = vary + ", " + name
+	}
+}
+
+// splitList splits a comma-separated list and drops the empty values.
+func splitList(value string) []string {
+	result := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ok is a handler that always succeeds.
+func ok(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{StatusCode: 200}, nil
+}
+
+// TestNewCORSPolicy tests that the CORS policy is read from the environment variables.
+func TestNewCORSPolicy(t *testing.T) {
+	os.Setenv("CORS_ALLOWED_ORIGINS", "https://a.com, https://b.com")
+	os.Setenv("CORS_MAX_AGE", "600")
+	os.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	defer os.Unsetenv("CORS_ALLOWED_ORIGINS")
+	defer os.Unsetenv("CORS_MAX_AGE")
+	defer os.Unsetenv("CORS_ALLOW_CREDENTIALS")
+
+	policy := NewCORSPolicy()
+
+	if !reflect.DeepEqual(policy.AllowedOrigins, []string{"https://a.com", "https://b.com"}) {
+		t.Error("The allowed origins were not expected to be ", policy.AllowedOrigins)
+	}
+	if policy.MaxAge != 600 || !policy.AllowCredentials {
+		t.Errorf("The maximum age and the credentials were not expected to be %d and %t.", policy.MaxAge,
+			policy.AllowCredentials)
+	}
+	if len(policy.AllowedMethods) == 0 || len(policy.AllowedHeaders) == 0 {
+		t.Error("The default methods and headers were expected to be kept.")
+	}
+}
+
+// TestCORSWithAllowlist tests that only the allowed origins are echoed.
+func TestCORSWithAllowlist(t *testing.T) {
+	policy := CORSPolicy{AllowedOrigins: []string{"https://a.com"}, AllowedMethods: []string{"GET"},
+		AllowCredentials: true, MaxAge: 600}
+	handler := CORS(policy)(ok)
+	testCases := []struct {
+		method         string
+		origin         string
+		expectedOrigin string
+		expectedMaxAge string
+	}{
+		{"GET", "https://a.com", "https://a.com", ""},
+		{"OPTIONS", "https://a.com", "https://a.com", "600"},
+		{"GET", "https://evil.com", "", ""},
+		{"GET", "", "", ""},
+	}
+
+	for _, testCase := range testCases {
+		response, _ := handler(events.APIGatewayProxyRequest{HTTPMethod: testCase.method,
+			Headers: map[string]string{"Origin": testCase.origin}})
+
+		if response.Headers["Access-Control-Allow-Origin"] != testCase.expectedOrigin {
+			t.Errorf("The allowed origin for %s was expected to be %s, but it was %s.", testCase.origin,
+				testCase.expectedOrigin, response.Headers["Access-Control-Allow-Origin"])
+		}
+		if (testCase.expectedOrigin != "") != (response.Headers["Access-Control-Allow-Credentials"] == "true") {
+			t.Errorf("The credentials for %s were not expected to be %s.", testCase.origin,
+				response.Headers["Access-Control-Allow-Credentials"])
+		}
+		if response.Headers["Access-Control-Max-Age"] != testCase.expectedMaxAge {
+			t.Errorf("The maximum age for %s %s was expected to be %s, but it was %s.", testCase.method,
+				testCase.origin, testCase.expectedMaxAge, response.Headers["Access-Control-Max-Age"])
+		}
+		if response.Headers["Vary"] != "Origin" {
+			t.Errorf("The Vary header was expected to be Origin, but it was %s.", response.Headers["Vary"])
+		}
+	}
+}
+
+// TestCORSWithWildcard tests that a wildcard policy allows every origin.
+func TestCORSWithWildcard(t *testing.T) {
+	handler := CORS(CORSPolicy{AllowedOrigins: []string{"*"}})(func(request events.APIGatewayProxyRequest) (
+		events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{Headers: map[string]string{"Vary": "Accept"}}, nil
+	})
+
+	response, _ := handler(events.APIGatewayProxyRequest{Headers: map[string]string{"Origin": "https://a.com"}})
+
+	if response.Headers["Access-Control-Allow-Origin"] != "*" {
+		t.Errorf("The allowed origin was expected to be *, but it was %s.", response.Headers["Access-Control-Allow-Origin"])
+	}
+	if response.Headers["Vary"] != "Accept, Origin" {
+		t.Errorf("The Vary header was expected to be Accept, Origin, but it was %s.", response.Headers["Vary"])
+	}
+}
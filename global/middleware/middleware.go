@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"mime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	"github.com/printezisn/serverless-blog-back/global/router"
+	"github.com/printezisn/serverless-blog-back/global/writer"
+)
+
+// RequestIDHeader is the header that carries the id of a request, in both the request and the response.
+const RequestIDHeader = "X-Request-Id"
+
+// RequestID gives every request an id, which is added to the headers of the request and of the response, and to the
+// body of error responses. The id of the API Gateway request is used if there is one, then the id that the caller has
+// sent, and otherwise a new one is generated.
+func RequestID(handler router.Handler) router.Handler {
+	return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		id := request.RequestContext.RequestID
+		if id == "" {
+			id = Header(request, RequestIDHeader)
+		}
+		if id == "" {
+			id = newRequestID()
+		}
+
+		headers := make(map[string]string, len(request.Headers)+1)
+		for name, value := range request.Headers {
+			if !strings.EqualFold(name, RequestIDHeader) {
+				headers[name] = value
+			}
+		}
+		headers[RequestIDHeader] = id
+		request.Headers = headers
+
+		response, err := handler(request)
+		response = writer.WithRequestID(response, id)
+		setDefaultHeader(&response, RequestIDHeader, id)
+
+		return response, err
+	}
+}
+
+// Logging logs the method, the path, the status code and the duration of every request.
+func Logging(handler router.Handler) router.Handler {
+	return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		start := time.Now()
+		response, err := handler(request)
+
+		log.Printf("Request %s: %s %s returned %d in %s.", Header(request, RequestIDHeader), request.HTTPMethod,
+			request.Path, response.StatusCode, time.Since(start))
+
+		return response, err
+	}
+}
+
+// Recover turns a panic of the handler into an internal server error, so that a single request cannot bring down the
+// whole function.
+func Recover(handler router.Handler) router.Handler {
+	return func(request events.APIGatewayProxyRequest) (response events.APIGatewayProxyResponse, err error) {
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				log.Println("A panic occurred while handling a request: ", recovered, "\n", string(debug.Stack()))
+
+				response = writer.Error(500)
+				err = nil
+			}
+		}()
+
+		return handler(request)
+	}
+}
+
+// RequireJSON rejects the requests with a body whose content type is not JSON. Requests without a content type are
+// accepted, since the body has always been JSON.
+func RequireJSON(handler router.Handler) router.Handler {
+	return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		contentType := Header(request, "Content-Type")
+		if request.Body != "" && contentType != "" && !isJSON(contentType) {
+			return writer.Error(415), nil
+		}
+
+		return handler(request)
+	}
+}
+
+// RequireRole returns a middleware that rejects the requests of callers that are not authenticated with a 401, and of
+// callers without a role or a higher one with a 403. It's meant for single routes, since the permissions on every
+// resource are checked by the services.
+func RequireRole(role auth.Role) router.Middleware {
+	return func(handler router.Handler) router.Handler {
+		return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			identity, ok := auth.FromRequest(request)
+			if !ok {
+				return writer.Error(401), nil
+			}
+			if !identity.HasRole(role) {
+				return writer.Error(403), nil
+			}
+
+			return handler(request)
+		}
+	}
+}
+
+// Header returns the value of a request header. Header names are case-insensitive.
+func Header(request events.APIGatewayProxyRequest, name string) string {
+	for key, value := range request.Headers {
+		if strings.EqualFold(key, name) {
+			return value
+		}
+	}
+
+	return ""
+}
+
+// isJSON checks if a content type is JSON, e.g. "application/json" or "application/merge-patch+json".
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+
+	return mediaType == "application/json" || (strings.HasPrefix(mediaType, "application/") &&
+		strings.HasSuffix(mediaType, "+json"))
+}
+
+// setDefaultHeader sets a header of a response, unless it's already set.
+func setDefaultHeader(response *events.APIGatewayProxyResponse, name string, value string) {
+	if response.Headers == nil {
+		response.Headers = map[string]string{}
+	}
+	if _, ok := response.Headers[name]; !ok {
+		response.Headers[name] = value
+	}
+}
+
+// newRequestID generates a random request id.
+func newRequestID() string {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return strings.ReplaceAll(time.Now().UTC().Format("20060102150405.000000000"), ".", "")
+	}
+
+	return hex.EncodeToString(bytes)
+}
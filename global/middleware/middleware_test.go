@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/auth"
+	"github.com/printezisn/serverless-blog-back/global/writer"
+)
+
+// echo is a handler that returns the request id of the request as the body.
+func echo(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	return events.APIGatewayProxyResponse{Body: Header(request, RequestIDHeader), StatusCode: 200}, nil
+}
+
+// TestRequestID tests that the request id is taken from the request context or the headers, or generated.
+func TestRequestID(t *testing.T) {
+	handler := RequestID(echo)
+	requests := []events.APIGatewayProxyRequest{
+		{RequestContext: events.APIGatewayProxyRequestContext{RequestID: "context"},
+			Headers: map[string]string{"x-request-id": "header"}},
+		{Headers: map[string]string{"x-request-id": "header"}},
+	}
+	expectedIDs := []string{"context", "header"}
+
+	for i, request := range requests {
+		response, _ := handler(request)
+
+		if response.Body != expectedIDs[i] || response.Headers[RequestIDHeader] != expectedIDs[i] {
+			t.Errorf("The request id was expected to be %s, but it was %s and %s.", expectedIDs[i], response.Body,
+				response.Headers[RequestIDHeader])
+		}
+	}
+
+	first, _ := handler(events.APIGatewayProxyRequest{})
+	second, _ := handler(events.APIGatewayProxyRequest{})
+	if first.Body == "" || first.Body == second.Body {
+		t.Errorf("Unique request ids were expected to be generated, but they were %s and %s.", first.Body, second.Body)
+	}
+}
+
+// TestRequestIDWithError tests that the request id is added to the body of error responses.
+func TestRequestIDWithError(t *testing.T) {
+	handler := RequestID(func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return writer.Error(404), nil
+	})
+	expectedBody := `{"StatusCode":404,"entity":null,"errors":[{"code":"not_found",` +
+		`"message":"The resource was not found."}],"requestId":"id"}`
+
+	response, _ := handler(events.APIGatewayProxyRequest{Headers: map[string]string{RequestIDHeader: "id"}})
+
+	if response.Body != expectedBody {
+		t.Errorf("The body was expected to be %s, but it was %s.", expectedBody, response.Body)
+	}
+}
+
+// TestRecover tests that a panic of the handler results in an internal server error.
+func TestRecover(t *testing.T) {
+	handler := Recover(func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		panic("failure")
+	})
+
+	response, err := handler(events.APIGatewayProxyRequest{})
+
+	if err != nil {
+		t.Error("No error was expected, but there was ", err)
+	}
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+}
+
+// TestRequireJSON tests that requests with a body that is not JSON are rejected.
+func TestRequireJSON(t *testing.T) {
+	handler := RequireJSON(echo)
+	testCases := []struct {
+		contentType string
+		body        string
+		statusCode  int
+	}{
+		{"", "{}", 200},
+		{"application/json; charset=utf-8", "{}", 200},
+		{"application/json-patch+json", "[]", 200},
+		{"text/plain", "", 200},
+		{"text/plain", "{}", 415},
+		{"application/x-www-form-urlencoded", "a=b", 415},
+	}
+
+	for _, testCase := range testCases {
+		response, _ := handler(events.APIGatewayProxyRequest{Body: testCase.body,
+			Headers: map[string]string{"Content-Type": testCase.contentType}})
+
+		if response.StatusCode != testCase.statusCode {
+			t.Errorf("The status code for %s was expected to be %d, but it was %d.", testCase.contentType,
+				testCase.statusCode, response.StatusCode)
+		}
+	}
+}
+
+// TestRequireRole tests that callers without the role are rejected.
+func TestRequireRole(t *testing.T) {
+	handler := RequireRole(auth.RoleEditor)(echo)
+	testCases := []struct {
+		claims     map[string]interface{}
+		statusCode int
+	}{
+		{nil, 401},
+		{map[string]interface{}{"sub": "user"}, 403},
+		{map[string]interface{}{"sub": "user", "cognito:groups": "author"}, 403},
+		{map[string]interface{}{"sub": "user", "cognito:groups": "editor"}, 200},
+		{map[string]interface{}{"sub": "user", "cognito:groups": "admin"}, 200},
+	}
+
+	for _, testCase := range testCases {
+		request := events.APIGatewayProxyRequest{}
+		if testCase.claims != nil {
+			request.RequestContext.Authorizer = map[string]interface{}{"claims": testCase.claims}
+		}
+
+		response, _ := handler(request)
+
+		if response.StatusCode != testCase.statusCode {
+			t.Errorf("The status code for %v was expected to be %d, but it was %d.", testCase.claims,
+				testCase.statusCode, response.StatusCode)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package model
+
+// Error represents an error of an operation. The code is stable and meant for clients to handle the error, the field
+// is the JSON name of the field that caused it, if there is one, and the message describes it to people.
+type Error struct {
+	Code    string `json:"code"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// The codes of the errors.
+const (
+	// CodeInvalidInput is the code of a request that cannot be read, e.g. a body that is not JSON.
+	CodeInvalidInput = "invalid_input"
+	// CodeInvalid is the code of a value that is not valid for any other reason.
+	CodeInvalid = "invalid"
+	// CodeRequired is the code of a value that is missing.
+	CodeRequired = "required"
+	// CodeTooLong is the code of a value that has too many characters or items.
+	CodeTooLong = "too_long"
+	// CodeTooSmall is the code of a number that is smaller than allowed.
+	CodeTooSmall = "too_small"
+	// CodeOutOfRange is the code of a number that is not within the allowed range.
+	CodeOutOfRange = "out_of_range"
+	// CodeNotAllowed is the code of a value that is not one of the allowed ones.
+	CodeNotAllowed = "not_allowed"
+	// CodeReserved is the code of a value that is reserved for other uses, e.g. an id.
+	CodeReserved = "reserved"
+	// CodeInvalidFormat is the code of a value that doesn't have the expected format, e.g. a URL.
+	CodeInvalidFormat = "invalid_format"
+	// CodeInvalidTransition is the code of a status that cannot be reached from the current one.
+	CodeInvalidTransition = "invalid_transition"
+	// CodeUnauthorized is the code of a request that is not authenticated.
+	CodeUnauthorized = "unauthorized"
+	// CodeForbidden is the code of an operation that the caller is not allowed to do.
+	CodeForbidden = "forbidden"
+	// CodeNotFound is the code of a resource that doesn't exist.
+	CodeNotFound = "not_found"
+	// CodeMethodNotAllowed is the code of a method that the resource doesn't support.
+	CodeMethodNotAllowed = "method_not_allowed"
+	// CodeConflict is the code of a resource that has changed in the meantime or already exists.
+	CodeConflict = "conflict"
+	// CodePreconditionFailed is the code of a resource that doesn't match the If-Match header.
+	CodePreconditionFailed = "precondition_failed"
+	// CodeTooLarge is the code of a resource that is too large to be stored.
+	CodeTooLarge = "too_large"
+	// CodeUnsupportedMediaType is the code of a body whose content type is not supported.
+	CodeUnsupportedMediaType = "unsupported_media_type"
+	// CodeThrottled is the code of a request that failed because the storage is throttled.
+	CodeThrottled = "throttled"
+	// CodeUnexpected is the code of every other error.
+	CodeUnexpected = "unexpected"
+)
+
+// statusErrors holds the errors that describe the status codes of failed operations.
+var statusErrors = map[int]Error{
+	400: {Code: CodeInvalidInput, Message: "The input is invalid."},
+	401: {Code: CodeUnauthorized, Message: "The request is not authorized."},
+	403: {Code: CodeForbidden, Message: "The caller is not allowed to do this."},
+	404: {Code: CodeNotFound, Message: "The resource was not found."},
+	405: {Code: CodeMethodNotAllowed, Message: "The method is not allowed."},
+	409: {Code: CodeConflict, Message: "The resource has been changed in the meantime."},
+	412: {Code: CodePreconditionFailed, Message: "The resource doesn't match the If-Match header."},
+	413: {Code: CodeTooLarge, Message: "The resource is too large."},
+	415: {Code: CodeUnsupportedMediaType, Message: "The content type is not supported."},
+	503: {Code: CodeThrottled, Message: "The service is busy. Please try again later."},
+}
+
+// NewError creates an error that isn't caused by a specific field.
+func NewError(code string, message string) Error {
+	return Error{Code: code, Message: message}
+}
+
+// FieldError creates an error that is caused by a field.
+func FieldError(code string, field string, message string) Error {
+	return Error{Code: code, Field: field, Message: message}
+}
+
+// StatusError returns the error that describes the status code of a failed operation. Unknown status codes are
+// described as unexpected errors.
+func StatusError(statusCode int) Error {
+	if err, ok := statusErrors[statusCode]; ok {
+		return err
+	}
+
+	return Error{Code: CodeUnexpected, Message: "An unexpected error occurred."}
+}
+
+// Error returns the message of the error.
+func (err Error) Error() string {
+	return err.Message
+}
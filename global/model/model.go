@@ -3,6 +3,6 @@ package model
 // Response represents the response of an operation.
 type Response struct {
 	Entity     interface{} `json:"entity"`
-	Errors     []string    `json:"errors"`
+	Errors     []Error     `json:"errors"`
 	StatusCode int
 }
@@ -0,0 +1,83 @@
+package patch
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestMerge tests that a merge patch replaces, adds and removes members, recursively.
+func TestMerge(t *testing.T) {
+	document := `{"a":"b","c":{"d":"e","f":"g"},"h":[1,2]}`
+	patch := `{"a":"z","c":{"f":null},"h":[3],"i":true}`
+	expected := `{"a":"z","c":{"d":"e"},"h":[3],"i":true}`
+
+	result, err := Merge([]byte(document), []byte(patch))
+
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if string(result) != expected {
+		t.Errorf("The document was expected to be %s, but it was %s.", expected, result)
+	}
+}
+
+// TestMergeWithInvalidPatch tests that a merge patch that is not JSON is rejected.
+func TestMergeWithInvalidPatch(t *testing.T) {
+	if _, err := Merge([]byte(`{}`), []byte(`{`)); !errors.Is(err, ErrInvalid) {
+		t.Error("The error was expected to be ErrInvalid, but it was ", err)
+	}
+}
+
+// TestApply tests that the operations of a JSON patch are applied in order.
+func TestApply(t *testing.T) {
+	document := `{"a":"b","c":["d","e"],"f":{"g":1}}`
+	patch := `[
+		{"op":"test","path":"/a","value":"b"},
+		{"op":"replace","path":"/a","value":"z"},
+		{"op":"add","path":"/c/1","value":"x"},
+		{"op":"add","path":"/c/-","value":"y"},
+		{"op":"remove","path":"/c/0"},
+		{"op":"copy","from":"/f","path":"/h"},
+		{"op":"move","from":"/f/g","path":"/i~1j"}
+	]`
+	expected := `{"a":"z","c":["x","e","y"],"f":{},"h":{"g":1},"i/j":1}`
+
+	result, err := Apply([]byte(document), []byte(patch))
+
+	if err != nil {
+		t.Fatal("No error was expected, but there was ", err)
+	}
+	if string(result) != expected {
+		t.Errorf("The document was expected to be %s, but it was %s.", expected, result)
+	}
+}
+
+// TestApplyWithFailedTest tests that a JSON patch fails when a test operation doesn't match.
+func TestApplyWithFailedTest(t *testing.T) {
+	patch := `[{"op":"replace","path":"/a","value":"z"},{"op":"test","path":"/a","value":"b"}]`
+
+	if _, err := Apply([]byte(`{"a":"b"}`), []byte(patch)); !errors.Is(err, ErrTestFailed) {
+		t.Error("The error was expected to be ErrTestFailed, but it was ", err)
+	}
+}
+
+// TestApplyWithInvalidOperations tests that JSON patches with invalid operations are rejected.
+func TestApplyWithInvalidOperations(t *testing.T) {
+	patches := []string{
+		`{"op":"add","path":"/b","value":1}`,
+		`[{"op":"unknown","path":"/a"}]`,
+		`[{"op":"add","path":"/b"}]`,
+		`[{"op":"add","path":"b","value":1}]`,
+		`[{"op":"remove","path":"/b"}]`,
+		`[{"op":"replace","path":"/b/c","value":1}]`,
+		`[{"op":"add","path":"/c/5","value":1}]`,
+		`[{"op":"add","path":"/c/01","value":1}]`,
+		`[{"op":"move","from":"/a","path":"/a/b"}]`,
+	}
+
+	for _, patch := range patches {
+		if _, err := Apply([]byte(`{"a":{},"c":[1]}`), []byte(patch)); !errors.Is(err, ErrInvalid) {
+			t.Errorf("The error of %s was expected to be ErrInvalid, but it was %v.", patch, err)
+		}
+	}
+}
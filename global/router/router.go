@@ -0,0 +1,188 @@
+package router
+
+import (
+	"encoding/base64"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// Handler handles an API Gateway request.
+type Handler func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// Middleware wraps a handler with behaviour that is shared by every route.
+type Middleware func(handler Handler) Handler
+
+// Router dispatches API Gateway requests to the handler of the route that matches their method and path. Paths are
+// matched against templates such as "/posts/{id}/revisions/{revision}", where the literal segments are
+// case-insensitive and the values of the parameters replace the path parameters of the request.
+type Router struct {
+	routes      []route
+	middlewares []Middleware
+	// NotFound handles the requests whose path matches no route.
+	NotFound Handler
+	// MethodNotAllowed handles the requests whose path matches a route, but not its method. The "Allow" header is
+	// added to its response.
+	MethodNotAllowed Handler
+	// Options handles the OPTIONS requests whose path matches a route without an OPTIONS handler. If it's nil, they
+	// are handled by MethodNotAllowed. The "Allow" header is added to its response.
+	Options Handler
+}
+
+// route represents a handler for a method and a path template.
+type route struct {
+	method   string
+	segments []string
+	handler  Handler
+}
+
+// New creates a new router without any routes.
+func New() *Router {
+	return &Router{
+		NotFound:         textHandler(404, "The resource was not found."),
+		MethodNotAllowed: textHandler(405, "The method is not allowed."),
+	}
+}
+
+// Handle registers the handler of a method and a path template. Routes are matched in the order they are registered.
+func (router *Router) Handle(method string, template string, handler Handler) {
+	router.routes = append(router.routes, route{
+		method:   strings.ToUpper(method),
+		segments: splitPath(template),
+		handler:  handler,
+	})
+}
+
+// Use adds middlewares around the handlers of every request, including the requests that match no route. The first
+// middleware is the outermost one.
+func (router *Router) Use(middlewares ...Middleware) {
+	router.middlewares = append(router.middlewares, middlewares...)
+}
+
+// Route dispatches a request to the handler of the first route that matches it, through the middlewares. A
+// base64-encoded body is decoded first, so that the middlewares and the handlers always read plain text, whichever
+// trigger or adapter the request came from.
+func (router *Router) Route(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	handler := router.dispatch
+	for i := len(router.middlewares) - 1; i >= 0; i-- {
+		handler = router.middlewares[i](handler)
+	}
+
+	return handler(decodeBody(request))
+}
+
+// dispatch calls the handler of the first route that matches a request.
+func (router *Router) dispatch(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+	method := strings.ToUpper(request.HTTPMethod)
+	segments := splitPath(request.Path)
+
+	allowed := map[string]bool{}
+	for _, route := range router.routes {
+		parameters, ok := route.match(segments)
+		if !ok {
+			continue
+		}
+		if route.method != method {
+			allowed[route.method] = true
+			continue
+		}
+
+		request.PathParameters = parameters
+
+		return route.handler(request)
+	}
+
+	if len(allowed) == 0 {
+		return router.NotFound(request)
+	}
+
+	handler := router.MethodNotAllowed
+	if method == "OPTIONS" && router.Options != nil {
+		handler = router.Options
+		allowed["OPTIONS"] = true
+	}
+	response, err := handler(request)
+	if response.Headers == nil {
+		response.Headers = map[string]string{}
+	}
+	response.Headers["Allow"] = allowHeader(allowed)
+
+	return response, err
+}
+
+// decodeBody returns the request with its body decoded, if it's base64-encoded. A body that is not valid base64 is
+// kept as it is.
+func decodeBody(request events.APIGatewayProxyRequest) events.APIGatewayProxyRequest {
+	if !request.IsBase64Encoded {
+		return request
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(request.Body)
+	if err != nil {
+		return request
+	}
+	request.Body = string(decoded)
+	request.IsBase64Encoded = false
+
+	return request
+}
+
+// match checks if the segments of a path match the template of the route and returns the values of its parameters.
+func (route route) match(segments []string) (map[string]string, bool) {
+	if len(segments) != len(route.segments) {
+		return nil, false
+	}
+
+	parameters := map[string]string{}
+	for i, segment := range route.segments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			value, err := url.PathUnescape(segments[i])
+			if err != nil {
+				value = segments[i]
+			}
+			parameters[segment[1:len(segment)-1]] = value
+		} else if !strings.EqualFold(segment, segments[i]) {
+			return nil, false
+		}
+	}
+
+	return parameters, true
+}
+
+// splitPath splits a path into its segments. Leading and trailing slashes and the query string are ignored.
+func splitPath(path string) []string {
+	if index := strings.Index(path, "?"); index >= 0 {
+		path = path[:index]
+	}
+	path = strings.Trim(path, "/")
+	if path == "" {
+		return []string{}
+	}
+
+	return strings.Split(path, "/")
+}
+
+// allowHeader returns the value of the "Allow" header for a set of methods, in alphabetical order.
+func allowHeader(allowed map[string]bool) string {
+	methods := make([]string, 0, len(allowed))
+	for method := range allowed {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	return strings.Join(methods, ",")
+}
+
+// textHandler returns a handler that always responds with a status code and a text body.
+func textHandler(statusCode int, body string) Handler {
+	return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{
+				Body:       body,
+				Headers:    map[string]string{"Content-Type": "application/text"},
+				StatusCode: statusCode,
+			},
+			nil
+	}
+}
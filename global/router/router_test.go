@@ -0,0 +1,155 @@
+package router
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// newTestRouter returns a router whose handlers respond with their name and the path parameters of the request.
+func newTestRouter() *Router {
+	router := New()
+	handler := func(name string) Handler {
+		return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+			return events.APIGatewayProxyResponse{
+					Body:       name + ":" + request.PathParameters["id"] + ":" + request.PathParameters["revision"],
+					StatusCode: 200,
+				},
+				nil
+		}
+	}
+
+	router.Handle("GET", "/posts", handler("list"))
+	router.Handle("GET", "/posts/{id}", handler("get"))
+	router.Handle("DELETE", "/posts/{id}", handler("delete"))
+	router.Handle("GET", "/posts/{id}/revisions/{revision}", handler("revision"))
+
+	return router
+}
+
+// TestRoute tests that requests are dispatched to the route that matches their method and path.
+func TestRoute(t *testing.T) {
+	router := newTestRouter()
+	testCases := []struct {
+		method   string
+		path     string
+		expected string
+	}{
+		{"GET", "/posts", "list::"},
+		{"get", "/Posts/", "list::"},
+		{"GET", "/posts?tag=a/b", "list::"},
+		{"GET", "/posts/Some%20Id", "get:Some Id:"},
+		{"DELETE", "/posts/id", "delete:id:"},
+		{"GET", "/posts/id/revisions/2", "revision:id:2"},
+	}
+
+	for _, testCase := range testCases {
+		response, _ := router.Route(events.APIGatewayProxyRequest{HTTPMethod: testCase.method, Path: testCase.path,
+			PathParameters: map[string]string{"id": "greedy/value"}})
+
+		if response.StatusCode != 200 || response.Body != testCase.expected {
+			t.Errorf("The response of %s %s was expected to be %s, but it was %d %s.", testCase.method, testCase.path,
+				testCase.expected, response.StatusCode, response.Body)
+		}
+	}
+}
+
+// TestRouteWithNotFound tests that requests whose path matches no route are not found.
+func TestRouteWithNotFound(t *testing.T) {
+	router := newTestRouter()
+
+	for _, path := range []string{"/", "/postsXYZ", "/posts/id/other", "/posts/id/revisions/2/restore"} {
+		response, _ := router.Route(events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: path})
+
+		if response.StatusCode != 404 {
+			t.Errorf("The status code of %s was expected to be 404, but it was %d.", path, response.StatusCode)
+		}
+	}
+}
+
+// TestRouteWithMethodNotAllowed tests that requests whose path matches a route, but not its method, are rejected with
+// the allowed methods.
+func TestRouteWithMethodNotAllowed(t *testing.T) {
+	router := newTestRouter()
+
+	response, _ := router.Route(events.APIGatewayProxyRequest{HTTPMethod: "PUT", Path: "/posts/id"})
+
+	if response.StatusCode != 405 {
+		t.Errorf("The status code was expected to be 405, but it was %d.", response.StatusCode)
+	}
+	if response.Headers["Allow"] != "DELETE,GET" {
+		t.Errorf("The Allow header was expected to be DELETE,GET, but it was %s.", response.Headers["Allow"])
+	}
+}
+
+// TestRouteWithOptions tests that OPTIONS requests are answered by the Options handler when there is one.
+func TestRouteWithOptions(t *testing.T) {
+	router := newTestRouter()
+	request := events.APIGatewayProxyRequest{HTTPMethod: "OPTIONS", Path: "/posts/id"}
+
+	if response, _ := router.Route(request); response.StatusCode != 405 {
+		t.Errorf("The status code was expected to be 405, but it was %d.", response.StatusCode)
+	}
+
+	router.Options = textHandler(200, "Success")
+	response, _ := router.Route(request)
+
+	if response.StatusCode != 200 {
+		t.Errorf("The status code was expected to be 200, but it was %d.", response.StatusCode)
+	}
+	if response.Headers["Allow"] != "DELETE,GET,OPTIONS" {
+		t.Errorf("The Allow header was expected to be DELETE,GET,OPTIONS, but it was %s.", response.Headers["Allow"])
+	}
+}
+
+// TestRouteWithMiddlewares tests that middlewares wrap every request in the order they are added.
+func TestRouteWithMiddlewares(t *testing.T) {
+	router := newTestRouter()
+	middleware := func(name string) Middleware {
+		return func(handler Handler) Handler {
+			return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+				response, err := handler(request)
+				response.Body = name + "(" + response.Body + ")"
+
+				return response, err
+			}
+		}
+	}
+	router.Use(middleware("a"), middleware("b"))
+
+	testCases := map[string]string{"/posts": "a(b(list::))", "/other": "a(b(The resource was not found.))"}
+	for path, expected := range testCases {
+		response, _ := router.Route(events.APIGatewayProxyRequest{HTTPMethod: "GET", Path: path})
+
+		if response.Body != expected {
+			t.Errorf("The body of %s was expected to be %s, but it was %s.", path, expected, response.Body)
+		}
+	}
+}
+
+// TestRouteWithBase64Body tests that a base64-encoded body is decoded before the middlewares and the handlers see it,
+// and that the other bodies are passed as they are.
+func TestRouteWithBase64Body(t *testing.T) {
+	router := New()
+	router.Handle("PUT", "/posts", func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		if request.IsBase64Encoded {
+			return events.APIGatewayProxyResponse{StatusCode: 400}, nil
+		}
+
+		return events.APIGatewayProxyResponse{Body: request.Body, StatusCode: 200}, nil
+	})
+
+	body := `{"title":"title"}`
+	testCases := []events.APIGatewayProxyRequest{
+		{HTTPMethod: "PUT", Path: "/posts", Body: base64.StdEncoding.EncodeToString([]byte(body)), IsBase64Encoded: true},
+		{HTTPMethod: "PUT", Path: "/posts", Body: body},
+	}
+	for _, request := range testCases {
+		response, _ := router.Route(request)
+
+		if response.StatusCode != 200 || response.Body != body {
+			t.Errorf("The body was expected to be %s, but it was %s.", body, response.Body)
+		}
+	}
+}
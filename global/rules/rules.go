@@ -0,0 +1,129 @@
+// Package rules provides validation rules whose errors carry the code of the error, so that clients can tell why a
+// field is not valid, and converts the errors of a validation to the errors of a response.
+package rules
+
+import (
+	"errors"
+	"log"
+	"regexp"
+	"sort"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/go-ozzo/ozzo-validation/is"
+	"github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// codedRule is a validation rule that gives a code to the error of another rule.
+type codedRule struct {
+	code string
+	rule validation.Rule
+}
+
+// Coded returns a rule that fails like another rule, with an error that has a code.
+func Coded(code string, rule validation.Rule) validation.Rule {
+	return codedRule{code: code, rule: rule}
+}
+
+// Validate validates a value with the wrapped rule and gives its error the code of the rule. Internal errors and the
+// errors of nested values are returned as they are.
+func (rule codedRule) Validate(value interface{}) error {
+	err := rule.rule.Validate(value)
+	switch err.(type) {
+	case nil, validation.InternalError, validation.Errors:
+		return err
+	default:
+		return model.Error{Code: rule.code, Message: err.Error()}
+	}
+}
+
+// Required returns a rule that checks that a value is not empty.
+func Required(message string) validation.Rule {
+	return Coded(model.CodeRequired, validation.Required.Error(message))
+}
+
+// MaxLength returns a rule that checks that a string, a slice or a map has up to a number of characters or items.
+func MaxLength(max int, message string) validation.Rule {
+	return Coded(model.CodeTooLong, validation.Length(0, max).Error(message))
+}
+
+// Min returns a rule that checks that a value is not smaller than a minimum.
+func Min(min interface{}, message string) validation.Rule {
+	return Coded(model.CodeTooSmall, validation.Min(min).Error(message))
+}
+
+// In returns a rule that checks that a value is one of the allowed ones.
+func In(message string, values ...interface{}) validation.Rule {
+	return Coded(model.CodeNotAllowed, validation.In(values...).Error(message))
+}
+
+// NotIn returns a rule that checks that a value is not one of the reserved ones.
+func NotIn(message string, values ...interface{}) validation.Rule {
+	return Coded(model.CodeReserved, validation.NotIn(values...).Error(message))
+}
+
+// Match returns a rule that checks that a string matches a regular expression.
+func Match(re *regexp.Regexp, message string) validation.Rule {
+	return Coded(model.CodeInvalidFormat, validation.Match(re).Error(message))
+}
+
+// MaxBytes returns a rule that checks that a string has up to a number of bytes. Unlike MaxLength, which counts
+// characters, it limits the size that the string takes up in storage.
+func MaxBytes(max int, message string) validation.Rule {
+	return Coded(model.CodeTooLong, validation.By(func(value interface{}) error {
+		if s, ok := value.(string); ok && len(s) > max {
+			return errors.New(message)
+		}
+
+		return nil
+	}))
+}
+
+// URL returns a rule that checks that a string is a URL.
+func URL(message string) validation.Rule {
+	return Coded(model.CodeInvalidFormat, is.URL.Error(message))
+}
+
+// Errors converts the error of a struct validation to the errors of a response. Errors that are not validation errors,
+// such as a rule that cannot validate a value, are logged and reported as unexpected, so that a request never brings
+// the whole function down.
+func Errors(err error) []model.Error {
+	if err == nil {
+		return []model.Error{}
+	}
+
+	validationErrors, ok := err.(validation.Errors)
+	if !ok {
+		log.Println("An unexpected error occurred while validating a model: ", err)
+		return []model.Error{model.NewError(model.CodeUnexpected, "The model could not be validated.")}
+	}
+
+	return FieldErrors(validationErrors)
+}
+
+// FieldErrors converts the errors of a struct validation to the errors of a response, sorted by field. The errors of
+// nested values are flattened to fields such as "tags.0", and errors without a code are considered invalid values.
+func FieldErrors(errs validation.Errors) []model.Error {
+	result := []model.Error{}
+	appendFieldErrors(&result, "", errs)
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Field < result[j].Field
+	})
+
+	return result
+}
+
+// appendFieldErrors appends the errors of a validation to a list, prefixing their fields.
+func appendFieldErrors(result *[]model.Error, prefix string, errs validation.Errors) {
+	for field, err := range errs {
+		field = prefix + field
+
+		switch err := err.(type) {
+		case validation.Errors:
+			appendFieldErrors(result, field+".", err)
+		case model.Error:
+			*result = append(*result, model.FieldError(err.Code, field, err.Message))
+		default:
+			*result = append(*result, model.FieldError(model.CodeInvalid, field, err.Error()))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package rules
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation"
+	"github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// TestCoded tests that the errors of a rule get the code, and that valid values have no errors.
+func TestCoded(t *testing.T) {
+	testCases := []struct {
+		rule          validation.Rule
+		value         interface{}
+		expectedError error
+	}{
+		{Required("required"), "", model.Error{Code: model.CodeRequired, Message: "required"}},
+		{Required("required"), "value", nil},
+		{MaxLength(2, "long"), "abc", model.Error{Code: model.CodeTooLong, Message: "long"}},
+		{Min(0, "small"), -1, model.Error{Code: model.CodeTooSmall, Message: "small"}},
+		{In("in", "a", "b"), "c", model.Error{Code: model.CodeNotAllowed, Message: "in"}},
+		{URL("url"), "not a url", model.Error{Code: model.CodeInvalidFormat, Message: "url"}},
+		{URL("url"), "https://example.com", nil},
+		{NotIn("reserved", "a"), "a", model.Error{Code: model.CodeReserved, Message: "reserved"}},
+		{Match(regexp.MustCompile("^[a-z]+$"), "match"), "A", model.Error{Code: model.CodeInvalidFormat,
+			Message: "match"}},
+		{MaxBytes(2, "bytes"), "αβ", model.Error{Code: model.CodeTooLong, Message: "bytes"}},
+		{MaxBytes(2, "bytes"), "ab", nil},
+	}
+
+	for _, testCase := range testCases {
+		err := testCase.rule.Validate(testCase.value)
+
+		if !reflect.DeepEqual(err, testCase.expectedError) {
+			t.Errorf("The error for %v was expected to be %v, but it was %v.", testCase.value, testCase.expectedError,
+				err)
+		}
+	}
+}
+
+// TestFieldErrors tests that the errors of a validation are flattened and sorted by field.
+func TestFieldErrors(t *testing.T) {
+	errs := validation.Errors{
+		"title": model.Error{Code: model.CodeRequired, Message: "required"},
+		"tags":  validation.Errors{"1": model.Error{Code: model.CodeTooLong, Message: "long"}},
+		"body":  errors.New("invalid"),
+	}
+	expectedErrors := []model.Error{
+		{Code: model.CodeInvalid, Field: "body", Message: "invalid"},
+		{Code: model.CodeTooLong, Field: "tags.1", Message: "long"},
+		{Code: model.CodeRequired, Field: "title", Message: "required"},
+	}
+
+	actualErrors := FieldErrors(errs)
+
+	if !reflect.DeepEqual(actualErrors, expectedErrors) {
+		t.Errorf("The errors were expected to be %v, but they were %v.", expectedErrors, actualErrors)
+	}
+}
+
+// TestErrors tests that a validation never fails with anything but the errors of a response.
+func TestErrors(t *testing.T) {
+	if errs := Errors(nil); errs == nil || len(errs) > 0 {
+		t.Error("No errors were expected, but there were ", errs)
+	}
+
+	errs := Errors(validation.NewInternalError(errors.New("internal")))
+	if len(errs) != 1 || errs[0].Code != model.CodeUnexpected {
+		t.Error("An unexpected error was expected, but there were ", errs)
+	}
+}
@@ -0,0 +1,49 @@
+// Package ulid generates ULIDs (https://github.com/ulid/spec): 128-bit identifiers that start with their creation
+// time in milliseconds, so that they sort in the order they were created, followed by 80 random bits.
+package ulid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"time"
+)
+
+// alphabet is the Crockford base32 alphabet, which ULIDs are encoded with.
+const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// New generates a ULID for the current time.
+func New() string {
+	return At(time.Now())
+}
+
+// At generates a ULID for a time.
+func At(t time.Time) string {
+	var id [16]byte
+	binary.BigEndian.PutUint64(id[:8], uint64(t.UnixNano()/int64(time.Millisecond))<<16)
+	if _, err := rand.Read(id[6:]); err != nil {
+		// The time part is enough to keep the ids in order, so a failure of the random source only makes collisions
+		// more likely.
+		binary.BigEndian.PutUint64(id[8:], uint64(t.UnixNano()))
+	}
+
+	return encode(id)
+}
+
+// encode encodes the 128 bits of a ULID to 26 characters, 5 bits each, after 2 leading zero bits.
+func encode(id [16]byte) string {
+	result := make([]byte, 26)
+	for i := range result {
+		// The bit position of the character, counting the 2 leading zero bits.
+		bit := i*5 - 2
+
+		var value int
+		for j := 0; j < 5; j++ {
+			if position := bit + j; position >= 0 && id[position/8]&(0x80>>(position%8)) != 0 {
+				value |= 1 << (4 - j)
+			}
+		}
+		result[i] = alphabet[value]
+	}
+
+	return string(result)
+}
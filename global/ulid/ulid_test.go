@@ -0,0 +1,36 @@
+package ulid
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestEncode tests that ULIDs are encoded like the examples of the specification.
+func TestEncode(t *testing.T) {
+	var max [16]byte
+	for i := range max {
+		max[i] = 0xFF
+	}
+
+	if id := encode([16]byte{}); id != "00000000000000000000000000" {
+		t.Errorf("The ULID was expected to be 00000000000000000000000000, but it was %s.", id)
+	}
+	if id := encode(max); id != "7ZZZZZZZZZZZZZZZZZZZZZZZZZ" {
+		t.Errorf("The ULID was expected to be 7ZZZZZZZZZZZZZZZZZZZZZZZZZ, but it was %s.", id)
+	}
+}
+
+// TestAt tests that ULIDs start with their time, sort by it and are unique.
+func TestAt(t *testing.T) {
+	first := At(time.Unix(1469918176, 385000000))
+	second := At(time.Unix(1469918176, 386000000))
+	third := At(time.Unix(1469918176, 386000000))
+
+	if !strings.HasPrefix(first, "01ARYZ6S41") || len(first) != 26 {
+		t.Errorf("The ULID was expected to start with 01ARYZ6S41, but it was %s.", first)
+	}
+	if first >= second || second == third {
+		t.Errorf("The ULIDs were expected to be ordered and unique, but they were %s, %s and %s.", first, second, third)
+	}
+}
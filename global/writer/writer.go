@@ -0,0 +1,83 @@
+package writer
+
+import (
+	"encoding/json"
+	"log"
+	"mime"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// Response returns an API Gateway response with the JSON form of the response of an operation and its status code.
+// A failed operation without errors gets the error that describes its status code, so that every error response has
+// at least one.
+func Response(response model.Response) events.APIGatewayProxyResponse {
+	if response.StatusCode >= 400 && len(response.Errors) == 0 {
+		response.Errors = []model.Error{model.StatusError(response.StatusCode)}
+	}
+
+	return JSON(response.StatusCode, response)
+}
+
+// Error returns an API Gateway response for a failed request with the given errors, or with the error that describes
+// the status code if there are none.
+func Error(statusCode int, errs ...model.Error) events.APIGatewayProxyResponse {
+	return Response(model.Response{Errors: errs, StatusCode: statusCode})
+}
+
+// JSON returns an API Gateway response with the JSON form of a value. If the value cannot be serialized, it returns
+// an internal server error instead.
+func JSON(statusCode int, value interface{}) events.APIGatewayProxyResponse {
+	body, err := json.Marshal(value)
+	if err != nil {
+		log.Println("An error occurred while serializing a response: ", err)
+
+		statusCode = 500
+		body, _ = json.Marshal(model.Response{Errors: []model.Error{model.StatusError(statusCode)},
+			StatusCode: statusCode})
+	}
+
+	return events.APIGatewayProxyResponse{
+		Body:       string(body),
+		Headers:    map[string]string{"Content-Type": "application/json"},
+		StatusCode: statusCode,
+	}
+}
+
+// Text returns an API Gateway response with a text body.
+func Text(statusCode int, body string) events.APIGatewayProxyResponse {
+	return events.APIGatewayProxyResponse{
+		Body:       body,
+		Headers:    map[string]string{"Content-Type": "application/text"},
+		StatusCode: statusCode,
+	}
+}
+
+// WithRequestID adds the id of the request to the body of a JSON error response as "requestId", so that clients can
+// report it. Other responses are returned as they are.
+func WithRequestID(response events.APIGatewayProxyResponse, id string) events.APIGatewayProxyResponse {
+	if response.StatusCode < 400 || response.IsBase64Encoded || !isJSON(response.Headers["Content-Type"]) {
+		return response
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(response.Body), &body); err != nil || body == nil {
+		return response
+	}
+	body["requestId"], _ = json.Marshal(id)
+	bytes, err := json.Marshal(body)
+	if err != nil {
+		return response
+	}
+	response.Body = string(bytes)
+
+	return response
+}
+
+// isJSON checks if a content type is the one of the JSON responses.
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+
+	return err == nil && mediaType == "application/json"
+}
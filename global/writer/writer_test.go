@@ -0,0 +1,78 @@
+package writer
+
+import (
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/printezisn/serverless-blog-back/global/model"
+)
+
+// TestResponse tests that the response of an operation is serialized with its status code.
+func TestResponse(t *testing.T) {
+	response := Response(model.Response{Entity: "entity", Errors: []model.Error{}, StatusCode: 201})
+	expectedBody := `{"entity":"entity","errors":[],"StatusCode":201}`
+
+	if response.StatusCode != 201 {
+		t.Errorf("The status code was expected to be 201, but it was %d.", response.StatusCode)
+	}
+	if response.Body != expectedBody {
+		t.Errorf("The body was expected to be %s, but it was %s.", expectedBody, response.Body)
+	}
+	if response.Headers["Content-Type"] != "application/json" {
+		t.Errorf("The content type was expected to be application/json, but it was %s.", response.Headers["Content-Type"])
+	}
+}
+
+// TestJSONWithSerializationError tests that a value that cannot be serialized results in an internal server error.
+func TestJSONWithSerializationError(t *testing.T) {
+	response := JSON(200, model.Response{Entity: make(chan int), StatusCode: 200})
+
+	if response.StatusCode != 500 {
+		t.Errorf("The status code was expected to be 500, but it was %d.", response.StatusCode)
+	}
+	if response.Body == "" {
+		t.Error("The body was expected to describe the error, but it was empty.")
+	}
+}
+
+// TestResponseWithoutErrors tests that a failed operation without errors gets the error of its status code.
+func TestResponseWithoutErrors(t *testing.T) {
+	response := Response(model.Response{Entity: "entity", Errors: []model.Error{}, StatusCode: 404})
+	expectedBody := `{"entity":"entity","errors":[{"code":"not_found","message":"The resource was not found."}],` +
+		`"StatusCode":404}`
+
+	if response.Body != expectedBody {
+		t.Errorf("The body was expected to be %s, but it was %s.", expectedBody, response.Body)
+	}
+}
+
+// TestError tests that the errors of a failed request are serialized with their fields.
+func TestError(t *testing.T) {
+	response := Error(400, model.FieldError(model.CodeRequired, "title", "The title is required."))
+	expectedBody := `{"entity":null,"errors":[{"code":"required","field":"title","message":"The title is required."}],` +
+		`"StatusCode":400}`
+
+	if response.StatusCode != 400 {
+		t.Errorf("The status code was expected to be 400, but it was %d.", response.StatusCode)
+	}
+	if response.Body != expectedBody {
+		t.Errorf("The body was expected to be %s, but it was %s.", expectedBody, response.Body)
+	}
+}
+
+// TestWithRequestID tests that the request id is only added to JSON error responses.
+func TestWithRequestID(t *testing.T) {
+	errorResponse := WithRequestID(Error(404), "id")
+	expectedBody := `{"StatusCode":404,"entity":null,"errors":[{"code":"not_found",` +
+		`"message":"The resource was not found."}],"requestId":"id"}`
+	if errorResponse.Body != expectedBody {
+		t.Errorf("The body was expected to be %s, but it was %s.", expectedBody, errorResponse.Body)
+	}
+
+	for _, response := range []events.APIGatewayProxyResponse{Response(model.Response{StatusCode: 200}),
+		Text(404, "text")} {
+		if body := WithRequestID(response, "id").Body; body != response.Body {
+			t.Errorf("The body was expected to be %s, but it was %s.", response.Body, body)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/aws/aws-lambda-go/events"
+	authorDynamodb "github.com/printezisn/serverless-blog-back/author/repository/dynamodb"
+	authorMemory "github.com/printezisn/serverless-blog-back/author/repository/memory"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/dynamodb"
+	"github.com/printezisn/serverless-blog-back/blogpost/repository/memory"
+	"github.com/printezisn/serverless-blog-back/global/cursor"
+	"github.com/printezisn/serverless-blog-back/global/httpadapter"
+	"github.com/printezisn/serverless-blog-back/global/router"
+)
+
+// localOptions holds the command-line options of the local server.
+type localOptions struct {
+	addr    string
+	repo    string
+	subject string
+	groups  string
+}
+
+// serveLocal serves the API over HTTP. The repositories are kept in memory, unless DynamoDB is selected, in which case
+// the DYNAMODB_ENDPOINT environment variable can point to DynamoDB Local. Since there is no Cognito authorizer, every
+// request is made by the caller of the options, if there is one.
+func serveLocal(options localOptions) {
+	if err := cursor.CheckSecret(); err != nil {
+		log.Println("WARNING: The pagination cursors are signed with the public default secret: ", err)
+	}
+
+	var handler router.Handler
+	switch options.repo {
+	case "memory":
+		posts, authors := memory.New(), authorMemory.New()
+		api := newHandler(&posts, &authors)
+		handler = api.Handle
+	case "dynamodb":
+		posts, authors := dynamodb.New(), authorDynamodb.New()
+		api := newHandler(&posts, &authors)
+		handler = api.Handle
+	default:
+		log.Fatalf("The repository %s is not supported.", options.repo)
+	}
+
+	if options.subject != "" {
+		handler = withCaller(handler, options.subject, options.groups)
+	}
+
+	log.Printf("Listening on %s.", options.addr)
+	log.Fatal(http.ListenAndServe(options.addr, httpadapter.New(handler)))
+}
+
+// withCaller returns a handler that adds the claims of a caller to every request, the way the Cognito authorizer of
+// the API Gateway does.
+func withCaller(handler router.Handler, subject string, groups string) router.Handler {
+	return func(request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		request.RequestContext.Authorizer = map[string]interface{}{"claims": map[string]interface{}{
+			"sub": subject, "cognito:username": subject, "cognito:groups": groups}}
+
+		return handler(request)
+	}
+}
@@ -1,16 +1,92 @@
 package main
 
 import (
+	"flag"
+	"log"
+	"os"
+
 	"github.com/aws/aws-lambda-go/lambda"
+	authorHandler "github.com/printezisn/serverless-blog-back/author/handler/regular"
+	authorDynamodb "github.com/printezisn/serverless-blog-back/author/repository/dynamodb"
+	authorRepo "github.com/printezisn/serverless-blog-back/author/repository/generic"
+	authorService "github.com/printezisn/serverless-blog-back/author/service/regular"
 	regularHandler "github.com/printezisn/serverless-blog-back/blogpost/handler/regular"
+	scheduledHandler "github.com/printezisn/serverless-blog-back/blogpost/handler/scheduled"
 	"github.com/printezisn/serverless-blog-back/blogpost/repository/dynamodb"
+	postRepo "github.com/printezisn/serverless-blog-back/blogpost/repository/generic"
 	regularService "github.com/printezisn/serverless-blog-back/blogpost/service/regular"
+	"github.com/printezisn/serverless-blog-back/global/cursor"
+	"github.com/printezisn/serverless-blog-back/global/lambdaadapter"
 )
 
 func main() {
+	local := flag.Bool("local", false, "Serve the API over HTTP instead of running as a Lambda function.")
+	backfill := flag.Bool("backfill", false, "Tag the blog posts in DynamoDB that are missing from the listings and "+
+		"exit.")
+	options := localOptions{}
+	flag.StringVar(&options.addr, "addr", ":8080", "The address that the local server listens to.")
+	flag.StringVar(&options.repo, "repo", "memory", "The repositories of the local server: memory or dynamodb.")
+	flag.StringVar(&options.subject, "subject", "", "The Cognito subject of the caller of every local request.")
+	flag.StringVar(&options.groups, "groups", "", "The comma-separated Cognito groups of the caller of every local "+
+		"request.")
+	flag.Parse()
+
+	if *local {
+		serveLocal(options)
+		return
+	}
+	if *backfill {
+		runBackfill()
+		return
+	}
+
+	// Cursors that are signed with the public default secret can be forged, so the function doesn't start without one.
+	if err := cursor.CheckSecret(); err != nil {
+		log.Fatal("The pagination cursors cannot be signed: ", err)
+	}
+
 	repo := dynamodb.New()
 	service := regularService.New(&repo)
-	handler := regularHandler.New(&service)
 
-	lambda.Start(handler.Handle)
+	// The same binary serves the API and the scheduled events. The HANDLER environment variable selects which one.
+	if os.Getenv("HANDLER") == "scheduled" {
+		handler := scheduledHandler.New(&service)
+		lambda.Start(handler.Handle)
+		return
+	}
+
+	authors := authorDynamodb.New()
+	handler := newHandler(&repo, &authors)
+
+	// The API_EVENT_TYPE environment variable selects the trigger of the API: "rest" for a REST API of the API Gateway,
+	// which is the default, "http" for an HTTP API or "alb" for an Application Load Balancer.
+	switch os.Getenv("API_EVENT_TYPE") {
+	case "http":
+		lambda.Start(lambdaadapter.HTTPAPI(handler.Handle))
+	case "alb":
+		lambda.Start(lambdaadapter.ALB(handler.Handle))
+	default:
+		lambda.Start(handler.Handle)
+	}
+}
+
+// newHandler creates the handler of the API on top of the repositories of blog posts and author profiles.
+func newHandler(posts postRepo.Repo, authors authorRepo.Repo) regularHandler.Handler {
+	service := regularService.New(posts)
+	authorsService := authorService.New(authors)
+
+	return regularHandler.New(&service, authorHandler.Routes(&authorsService))
+}
+
+// runBackfill sets the entityType attribute of the blog posts that were created before it existed. Until then, these
+// blog posts are missing from the listings, so it has to run once against every table that was created by an older
+// version.
+func runBackfill() {
+	repo := dynamodb.New()
+	count, err := repo.Backfill()
+	if err != nil {
+		log.Fatal("The blog posts could not be backfilled: ", err)
+	}
+
+	log.Printf("%d blog posts were backfilled.", count)
 }